@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// AnimationPending reports whether the page wants another frame: a
+// requestAnimationFrame callback is queued, or a CSS animation or transition
+// is running. With NeedsRedraw it lets a power-efficient host tick only when
+// the page has something to show:
+//
+//	if ui.AnimationPending() || ui.NeedsRedraw() {
+//		ui.Update()
+//	}
+//
+// Ultralight has no "needs animation" signal of its own, so the page reports
+// it. Tracking starts with the first call: until the page has reported (and
+// after each navigation, until the new page has) it returns true. Closed views
+// and views created with Options.DisableJavaScript, where the page can't
+// report, never have an animation pending.
+func (ui *UltralightUI) AnimationPending() bool {
+	if ui.closed.Load() || ui.jsDisabled {
+		return false
+	}
+	ui.animTracking = true
+	return !ui.animKnown || ui.animPending
+}
+
+// injectAnimationHelper wraps requestAnimationFrame and counts running CSS
+// animations and transitions, sending __animation whenever the page goes
+// from idle to wanting frames or back.
+func (ui *UltralightUI) injectAnimationHelper() {
+	ui.Eval(`(function(){
+if(window.__ulAnimInit)return;window.__ulAnimInit=1;
+var raf=window.requestAnimationFrame,caf=window.cancelAnimationFrame,queued={},n=0,css=0,last=null;
+function running(){
+if(!document.getAnimations)return css;
+var a=document.getAnimations(),c=0;for(var i=0;i<a.length;i++)if(a[i].playState==='running')c++;return c;
+}
+function report(){
+var p=n>0||running()>0;if(p===last)return;last=p;
+if(window.go&&window.go.send)window.go.send({action:'__animation',pending:p});
+}
+window.requestAnimationFrame=function(cb){
+var id=raf.call(window,function(t){if(queued[id]){delete queued[id];n--}try{cb(t)}finally{report()}});
+queued[id]=1;n++;report();return id;
+};
+window.cancelAnimationFrame=function(id){if(queued[id]){delete queued[id];n--}caf.call(window,id);report()};
+function start(){css++;report()}
+function end(){if(css>0)css--;report()}
+document.addEventListener('animationstart',start,true);
+document.addEventListener('animationend',end,true);
+document.addEventListener('animationcancel',end,true);
+document.addEventListener('transitionrun',start,true);
+document.addEventListener('transitionend',end,true);
+document.addEventListener('transitioncancel',end,true);
+report();
+})();`)
+}
+
+// handleAnimationMsg intercepts __animation messages sent by the animation
+// helper. Returns true if the message was consumed (caller should skip
+// OnMessage).
+func (ui *UltralightUI) handleAnimationMsg(msg string) bool {
+	if !strings.HasPrefix(msg, "{\"action\":\"__animation\"") {
+		return false
+	}
+	var data struct {
+		Action  string `json:"action"`
+		Pending bool   `json:"pending"`
+	}
+	if json.Unmarshal([]byte(msg), &data) != nil || data.Action != "__animation" {
+		return false
+	}
+	ui.animPending = data.Pending
+	ui.animKnown = true
+	return true
+}
@@ -6,7 +6,10 @@ package ultralightui
 import (
 	"errors"
 	"fmt"
+	"image"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -17,6 +20,65 @@ import (
 // ErrClosed is returned when calling methods on a closed UltralightUI.
 var ErrClosed = errors.New("ultralightui: UI is closed")
 
+// ErrNotReady is returned by synchronous calls such as EvalSync while the view
+// is still loading (see IsReady).
+var ErrNotReady = errors.New("ultralightui: view is not ready")
+
+// ErrUnsupported is returned when a feature needs a bridge symbol missing
+// from the loaded bridge build, e.g. an older ul_bridge library. The error
+// names the symbol; rebuild the bridge from bridge/ul_bridge.c to get it.
+var ErrUnsupported = errors.New("ultralightui: not supported by this bridge build")
+
+// ErrConcurrentUpdate is returned by Update and UpdateNoTick when another
+// Update of the same view is still running on a different goroutine.
+var ErrConcurrentUpdate = errors.New("ultralightui: concurrent Update on the same view")
+
+// View creation errors, matched with errors.Is against the error returned by
+// New, NewFromHTML, NewFromURL and the other constructors.
+var (
+	// ErrRendererNotInitialized means the bridge worker or the Ultralight
+	// renderer is not running, e.g. ul_init failed earlier.
+	ErrRendererNotInitialized = errors.New("ultralightui: renderer not initialized")
+	// ErrViewLimitReached means the bridge already holds its maximum of 16
+	// views; Close one before creating another.
+	ErrViewLimitReached = errors.New("ultralightui: view limit reached")
+	// ErrInvalidSize means the width or height is not positive.
+	ErrInvalidSize = errors.New("ultralightui: invalid view size")
+	// ErrViewCreateFailed means Ultralight could not create the view,
+	// usually because GPU or system memory ran out.
+	ErrViewCreateFailed = errors.New("ultralightui: Ultralight failed to create the view")
+	// ErrOutOfMemory means the bridge ran out of memory copying the page.
+	ErrOutOfMemory = errors.New("ultralightui: out of memory")
+)
+
+// createErrors maps the bridge's ul_create_view* error codes (CREATE_ERR_* in
+// ul_bridge.c) to their errors. Bridges older than 1.2.0 return -1 for a
+// full view table too.
+var createErrors = map[int32]error{
+	-1:  ErrRendererNotInitialized,
+	-2:  ErrViewLimitReached,
+	-3:  ErrInvalidSize,
+	-11: ErrViewCreateFailed,
+	-12: ErrOutOfMemory,
+	-13: ErrUnsupported, // DisableJavaScript on an SDK without ulViewConfigSetEnableJavaScript
+
+	createErrSessionClosed: ErrSessionClosed, // Go side only, see createView
+}
+
+// createError returns the error for the negative code returned by the bridge
+// function fn; unknown codes keep the raw number.
+func createError(fn string, code int32) error {
+	if err, ok := createErrors[code]; ok {
+		return fmt.Errorf("%s: %w (code %d)", fn, err, code)
+	}
+	return fmt.Errorf("%s failed with code %d", fn, code)
+}
+
+// sizeError is returned by the constructors for a non-positive width or height.
+func sizeError(width, height int) error {
+	return fmt.Errorf("%w: %dx%d", ErrInvalidSize, width, height)
+}
+
 func init() {
 	// Lock the main goroutine to an OS thread. Required because:
 	// 1. Ebiten's RunGame must execute on the main thread (macOS requirement)
@@ -25,33 +87,12 @@ func init() {
 	runtime.LockOSThread()
 }
 
-// Mouse event types (ULMouseEventType / ULMouseButton)
-const (
-	mouseEventTypeMoved = 0
-	mouseEventTypeDown  = 1
-	mouseEventTypeUp    = 2
-
-	mouseButtonNone  = 0
-	mouseButtonLeft  = 1
-	mouseButtonRight = 3
-)
-
 const scrollEventTypeByPixel = 0
 
-// Key event types for Ultralight
+// View creation flags (VIEW_FLAG_* in ul_bridge.c), applied via ul_set_view_flags.
 const (
-	keyEventRawKeyDown = 0
-	keyEventKeyDown    = 1
-	keyEventKeyUp      = 2
-	keyEventChar       = 3
-)
-
-// Key modifier bits
-const (
-	keyModAlt   = 1
-	keyModCtrl  = 2
-	keyModMeta  = 4
-	keyModShift = 8
+	viewFlagDisableImages     = 0x01
+	viewFlagDisableJavaScript = 0x02
 )
 
 var (
@@ -61,7 +102,7 @@ var (
 	ulViewLoadHTML          func(viewID int32, html string)
 	ulViewLoadURL           func(viewID int32, url string)
 	ulTick                  func()
-	ulViewGetPixels         func(viewID int32) uintptr
+	ulViewGetPixels         func(viewID int32) unsafe.Pointer // C memory: never moved by the GC
 	ulViewUnlockPixels      func(viewID int32)
 	ulViewGetWidth          func(viewID int32) uint32
 	ulViewGetHeight         func(viewID int32) uint32
@@ -71,9 +112,11 @@ var (
 	ulViewFireKey           func(viewID int32, keyType int32, vk int32, mods uint32, text string)
 	ulViewEvalJS            func(viewID int32, js string)
 	ulViewGetMessage        func(viewID int32, buf uintptr, bufSize int32) int32
+	ulViewGetMessageLen     func(viewID int32) int32
 	ulViewGetConsoleMessage func(viewID int32, buf uintptr, bufSize int32) int32
 	ulDestroy               func()
 	ulVfsRegister           func(path string, data uintptr, size int64) int32
+	ulVfsRegisterTyped      func(path string, data uintptr, size int64, mimeType string) int32
 	ulVfsClear              func()
 	ulVfsCount              func() int32
 	ulCreateViewAsync       func(width, height int32, url string) int32
@@ -85,41 +128,93 @@ var (
 	ulViewGetSurfaceHeight  func(viewID int32) int32
 	ulSupportsBinarySend    func() int32
 	ulViewSendBinary        func(viewID int32, propsJSON, binKey string, binData uintptr, binLen int32)
+	ulSetViewFlags          func(flags int32)
+	ulViewSetPaused         func(viewID int32, paused int32)
+	ulViewGetLastDirty      func(viewID int32, out *int32)
+	ulViewEvalSync          func(viewID int32, js string) int32
+	ulEvalResultLen         func() int32
+	ulEvalResultCopy        func(buf *byte, bufSize int32) int32
+	ulViewGetLoadState      func(viewID int32, out *int32)
+	ulViewNavigate          func(viewID int32, op int32) int32
+	ulViewGetLoadError      func(viewID int32, urlBuf *byte, urlSize int32, descBuf *byte, descSize int32) int32
+	ulViewIsDOMReady        func(viewID int32) int32
+	ulBridgeVersion         func() string
+	ulViewMemoryUsage       func(viewID int32) int64
+	ulMemoryUsageTotal      func() int64
+	ulViewCopyPixelsForce   func(viewID int32, dest uintptr, destSize int32) int32
+	ulViewCopyPixelsDirty   func(viewID int32, dest uintptr, destSize int32) int32
+	ulSetViewDeviceScale    func(permille int32)
+	ulViewSetDeviceScale    func(viewID int32, permille int32) int32
+	ulSetViewSession        func(sessionID int32)
+	ulCreateSession         func(name string, persistent int32) int32
+	ulDestroySession        func(sessionID int32) int32
+	ulSetViewUserAgent      func(ua string)
+	ulRendererInfo          func(buf *byte, bufSize int32) int32
+	ulVfsUnregister         func(path string) int32
+	ulViewGetConsoleLevel   func(viewID int32, buf uintptr, bufSize int32, level *int32) int32
+	ulViewNeedsPaint        func(viewID int32) int32
+	ulSetViewFontFamily     func(family string)
+	ulViewResize            func(viewID int32, width, height int32) int32
 )
 
 var (
-	bridgeOnce sync.Once
-	initErr    error
-	ulInitOnce sync.Once
-	ulInitErr  error
-	viewCount  atomic.Int32
+	bridgeMu     sync.Mutex
+	bridgeLoaded bool // latched on the first successful initBridge
+	ulInitMu     sync.Mutex
+	ulInitDone   bool // latched on the first successful ul_init
+	viewCount    atomic.Int32
+
+	// evalSyncMu serializes ul_view_eval_sync with reading its result.
+	evalSyncMu sync.Mutex
 )
 
-func initBridge(baseDir string) error {
-	bridgeOnce.Do(func() {
-		initErr = doInitBridge(baseDir)
-	})
-	return initErr
+// initBridge loads the bridge library at libPath and resolves its symbols.
+// Failures aren't cached, so a later call can retry with another path.
+func initBridge(libPath string) error {
+	bridgeMu.Lock()
+	defer bridgeMu.Unlock()
+	if bridgeLoaded {
+		return nil
+	}
+	if err := doInitBridge(libPath); err != nil {
+		return err
+	}
+	bridgeLoaded = true
+	return nil
 }
 
-// ensureULInit calls ul_init(baseDir, debug) once. Must be called after initBridge.
+// ensureULInit calls ul_init(baseDir, debug) until it succeeds once, so a
+// failure with a wrong BaseDir can be retried with the right one. Later calls
+// after the success do nothing, whatever their baseDir. Must be called after
+// initBridge.
 func ensureULInit(baseDir string, debug bool) error {
-	ulInitOnce.Do(func() {
-		d := int32(0)
-		if debug {
-			d = 1
-		}
-		if rc := ulInit(baseDir, d); rc != 0 {
-			ulInitErr = fmt.Errorf("ul_init failed with code %d", rc)
-		}
-	})
-	return ulInitErr
+	ulInitMu.Lock()
+	defer ulInitMu.Unlock()
+	if ulInitDone {
+		return nil
+	}
+	d := int32(0)
+	if debug {
+		d = 1
+	}
+	if rc := ulInit(baseDir, d); rc != 0 {
+		return fmt.Errorf("ul_init failed with code %d (base dir %q)", rc, baseDir)
+	}
+	ulInitDone = true
+	return nil
 }
 
 func registerView() {
 	viewCount.Add(1)
 }
 
+// ActiveViewCount returns the number of views created and not yet closed,
+// e.g. to assert in tests that every screen closes its view. A view closed
+// during an Update counts until that Update returns.
+func ActiveViewCount() int {
+	return int(viewCount.Load())
+}
+
 func unregisterView() {
 	if viewCount.Add(-1) < 0 {
 		viewCount.Store(0)
@@ -129,7 +224,10 @@ func unregisterView() {
 }
 
 // resolveAllSymbols registers all exported symbols from the bridge using
-// getSymbolAddr (defined in bridge_windows.go or bridge_unix.go).
+// getSymbolAddr (defined in bridge_windows.go or bridge_unix.go). Core
+// symbols are required; optional ones (added in later bridge versions) are
+// left nil when missing, and the features using them report ErrUnsupported
+// or fall back to older behavior.
 func resolveAllSymbols(handle uintptr) error {
 	for _, reg := range []struct {
 		fptr interface{}
@@ -172,15 +270,154 @@ func resolveAllSymbols(handle uintptr) error {
 		}
 		purego.RegisterFunc(reg.fptr, sym)
 	}
+	for _, reg := range []struct {
+		fptr interface{}
+		name string
+	}{
+		{&ulViewGetMessageLen, "ul_view_get_message_len"},
+		{&ulVfsRegisterTyped, "ul_vfs_register_typed"},
+		{&ulSetViewFlags, "ul_set_view_flags"},
+		{&ulViewSetPaused, "ul_view_set_paused"},
+		{&ulViewGetLastDirty, "ul_view_get_last_dirty"},
+		{&ulViewEvalSync, "ul_view_eval_sync"},
+		{&ulEvalResultLen, "ul_eval_result_len"},
+		{&ulEvalResultCopy, "ul_eval_result_copy"},
+		{&ulViewGetLoadState, "ul_view_get_load_state"},
+		{&ulViewNavigate, "ul_view_navigate"},
+		{&ulViewGetLoadError, "ul_view_get_load_error"},
+		{&ulViewIsDOMReady, "ul_view_is_dom_ready"},
+		{&ulBridgeVersion, "ul_bridge_version"},
+		{&ulViewMemoryUsage, "ul_view_memory_usage"},
+		{&ulMemoryUsageTotal, "ul_memory_usage_total"},
+		{&ulViewCopyPixelsForce, "ul_view_copy_pixels_rgba_force"},
+		{&ulViewCopyPixelsDirty, "ul_view_copy_pixels_rgba_dirty"},
+		{&ulSetViewDeviceScale, "ul_set_view_device_scale"},
+		{&ulViewSetDeviceScale, "ul_view_set_device_scale"},
+		{&ulSetViewSession, "ul_set_view_session"},
+		{&ulCreateSession, "ul_create_session"},
+		{&ulDestroySession, "ul_destroy_session"},
+		{&ulSetViewUserAgent, "ul_set_view_user_agent"},
+		{&ulRendererInfo, "ul_renderer_info"},
+		{&ulVfsUnregister, "ul_vfs_unregister"},
+		{&ulViewGetConsoleLevel, "ul_view_get_console_message_level"},
+		{&ulViewNeedsPaint, "ul_view_needs_paint"},
+		{&ulSetViewFontFamily, "ul_set_view_font_family"},
+		{&ulViewResize, "ul_view_resize"},
+	} {
+		if sym, err := getSymbolAddr(handle, reg.name); err == nil && sym != 0 {
+			purego.RegisterFunc(reg.fptr, sym)
+		}
+	}
 	return nil
 }
 
+// errUnsupported reports that the loaded bridge lacks symbol.
+func errUnsupported(symbol string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupported, symbol)
+}
+
+// BridgeVersion returns the version of the loaded bridge library, to check
+// compatibility at startup. The bridge must be loaded (Preload or any New*);
+// bridges older than ul_bridge_version report ErrUnsupported.
+func BridgeVersion() (string, error) {
+	if ulInit == nil {
+		return "", errors.New("ultralightui: bridge not loaded (call Preload first)")
+	}
+	if ulBridgeVersion == nil {
+		return "", errUnsupported("ul_bridge_version")
+	}
+	return ulBridgeVersion(), nil
+}
+
+// bridgeAtLeast reports whether the loaded bridge's version is at least min
+// (both "major.minor.patch"). Bridges without ul_bridge_version predate any
+// version this is asked about.
+func bridgeAtLeast(min string) bool {
+	if ulBridgeVersion == nil {
+		return false
+	}
+	have, want := strings.Split(ulBridgeVersion(), "."), strings.Split(min, ".")
+	for i, w := range want {
+		wn, _ := strconv.Atoi(w)
+		hn := 0
+		if i < len(have) {
+			hn, _ = strconv.Atoi(have[i])
+		}
+		if hn != wn {
+			return hn > wn
+		}
+	}
+	return true
+}
+
+// RendererInfo reports how Ultralight renders views: backend is "cpu" or
+// "gpu", and gpu is true for GPU-accelerated views. The bridge always uses the
+// CPU renderer, so views don't depend on GPU support (e.g. RDP sessions); a
+// blank view points elsewhere (page errors, Ebiten's graphics backend).
+// Returns ErrRendererNotInitialized until the first Preload or New* succeeds.
+func RendererInfo() (backend string, gpu bool, err error) {
+	if ulInit == nil {
+		return "", false, errors.New("ultralightui: bridge not loaded (call Preload first)")
+	}
+	if ulRendererInfo == nil {
+		return "", false, errUnsupported("ul_renderer_info")
+	}
+	var buf [32]byte
+	n := ulRendererInfo(&buf[0], int32(len(buf)))
+	if n < 0 {
+		return "", false, ErrRendererNotInitialized
+	}
+	return cString(buf[:]), n == 1, nil
+}
+
 func evalJS(viewID int32, js string) {
 	ulViewEvalJS(viewID, js)
 }
 
+// evalSyncUnsupported is the evalSync status when the bridge lacks ul_view_eval_sync.
+const evalSyncUnsupported = -3
+
+// evalSync evaluates js on the bridge worker and returns the result as a
+// string. The status is 0 on success, 1 if the script threw (the string is
+// the exception message) and negative if the view is missing or loading.
+func evalSync(viewID int32, js string) (string, int32) {
+	evalSyncMu.Lock()
+	defer evalSyncMu.Unlock()
+	if ulViewEvalSync == nil {
+		return "", evalSyncUnsupported
+	}
+	status := ulViewEvalSync(viewID, js)
+	if status < 0 {
+		return "", status
+	}
+	n := ulEvalResultLen()
+	if n <= 0 {
+		return "", status
+	}
+	// *byte (not uintptr) keeps buf on the heap: a small stack buffer could
+	// move if the stack grows during the call.
+	buf := make([]byte, n+1)
+	n = ulEvalResultCopy(&buf[0], int32(len(buf)))
+	return string(buf[:n]), status
+}
+
+// pollMessage dequeues the next go.send message into a buffer sized from
+// ulViewGetMessageLen, so payloads are never truncated. Older bridges without
+// it use a 64 KB stack buffer.
 func pollMessage(viewID int32) (string, bool) {
-	var buf [65536]byte
+	if ulViewGetMessageLen == nil {
+		var stackBuf [65536]byte
+		n := ulViewGetMessage(viewID, uintptr(unsafe.Pointer(&stackBuf[0])), int32(len(stackBuf)))
+		if n <= 0 {
+			return "", false
+		}
+		return string(stackBuf[:n]), true
+	}
+	size := ulViewGetMessageLen(viewID)
+	if size < 0 {
+		return "", false
+	}
+	buf := make([]byte, int(size)+1)
 	n := ulViewGetMessage(viewID, uintptr(unsafe.Pointer(&buf[0])), int32(len(buf)))
 	if n <= 0 {
 		return "", false
@@ -188,11 +425,30 @@ func pollMessage(viewID int32) (string, bool) {
 	return string(buf[:n]), true
 }
 
-func pollConsoleMessage(viewID int32) (string, bool) {
+// lastDirtyRect returns the dirty bounds of the view's last pixel copy.
+func lastDirtyRect(viewID int32) image.Rectangle {
+	if ulViewGetLastDirty == nil {
+		return image.Rectangle{}
+	}
+	var r [4]int32
+	ulViewGetLastDirty(viewID, &r[0]) // pointer arg: r escapes, can't move mid-call
+	return image.Rect(int(r[0]), int(r[1]), int(r[2]), int(r[3]))
+}
+
+// pollConsoleMessage pops the view's oldest JS console message and its
+// ULMessageLevel (consoleLog on bridges without levels).
+func pollConsoleMessage(viewID int32) (string, int32, bool) {
 	var buf [8192]byte
-	n := ulViewGetConsoleMessage(viewID, uintptr(unsafe.Pointer(&buf[0])), int32(len(buf)))
+	level := int32(consoleLog)
+	var n int32
+	switch {
+	case ulViewGetConsoleLevel != nil:
+		n = ulViewGetConsoleLevel(viewID, uintptr(unsafe.Pointer(&buf[0])), int32(len(buf)), &level)
+	case ulViewGetConsoleMessage != nil:
+		n = ulViewGetConsoleMessage(viewID, uintptr(unsafe.Pointer(&buf[0])), int32(len(buf)))
+	}
 	if n <= 0 {
-		return "", false
+		return "", 0, false
 	}
-	return string(buf[:n]), true
+	return string(buf[:n]), level, true
 }
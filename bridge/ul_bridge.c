@@ -104,18 +104,34 @@ typedef void         (*PFN_ulDestroyViewConfig)(ULViewConfig);
 typedef void         (*PFN_ulVCSetIsAccelerated)(ULViewConfig, bool);
 typedef void         (*PFN_ulVCSetIsTransparent)(ULViewConfig, bool);
 typedef void         (*PFN_ulVCSetInitialDeviceScale)(ULViewConfig, double);
+typedef void         (*PFN_ulVCSetEnableImages)(ULViewConfig, bool);
+typedef void         (*PFN_ulVCSetEnableJavaScript)(ULViewConfig, bool);
+typedef void         (*PFN_ulVCSetUserAgent)(ULViewConfig, ULString);
+typedef void         (*PFN_ulVCSetFontFamilyStandard)(ULViewConfig, ULString);
 typedef ULView       (*PFN_ulCreateView)(ULRenderer, unsigned int, unsigned int, ULViewConfig, ULSession);
 typedef void         (*PFN_ulDestroyView)(ULView);
 typedef void         (*PFN_ulViewLoadHTML)(ULView, ULString);
 typedef void         (*PFN_ulViewLoadURL)(ULView, ULString);
+typedef bool         (*PFN_ulViewCanGo)(ULView);
+typedef void         (*PFN_ulViewGo)(ULView);  /* GoBack, GoForward, Stop */
+typedef void         (*PFN_ulViewSetDeviceScale)(ULView, double);
+typedef void         (*PFN_ulViewResize)(ULView, unsigned int, unsigned int);
+typedef ULSession    (*PFN_ulCreateSession)(ULRenderer, bool, ULString);
+typedef void         (*PFN_ulDestroySession)(ULSession);
 typedef ULSurface    (*PFN_ulViewGetSurface)(ULView);
 typedef void         (*PFN_ulViewFocus)(ULView);
+typedef void         (*PFN_ulViewSetDisplayId)(ULView, unsigned int);
+typedef bool         (*PFN_ulViewGetNeedsPaint)(ULView);
 typedef ULString     (*PFN_ulViewEvaluateScript)(ULView, ULString, ULString*);
 typedef void (*ULConsoleCallback)(void*, ULView, ULMessageSource, ULMessageLevel, ULString, unsigned int, unsigned int, ULString);
 typedef void (*PFN_ulViewSetConsoleCallback)(ULView, ULConsoleCallback, void*);
 /* DOMReady callback: fired when DOMContentLoaded triggers (JS context is stable) */
 typedef void (*ULDOMReadyCallback)(void*, ULView, unsigned long long, bool, ULString);
 typedef void (*PFN_ulViewSetDOMReadyCallback)(ULView, ULDOMReadyCallback, void*);
+/* Load lifecycle callbacks (BeginLoading/FinishLoading/WindowObjectReady share the DOMReady signature) */
+typedef void (*PFN_ulViewSetLoadCallback)(ULView, ULDOMReadyCallback, void*);
+typedef void (*ULFailLoadingCallback)(void*, ULView, unsigned long long, bool, ULString, ULString, ULString, int);
+typedef void (*PFN_ulViewSetFailLoadingCallback)(ULView, ULFailLoadingCallback, void*);
 typedef ULMouseEvent  (*PFN_ulCreateMouseEvent)(int, int, int, int);
 typedef void          (*PFN_ulDestroyMouseEvent)(ULMouseEvent);
 typedef void          (*PFN_ulViewFireMouseEvent)(ULView, ULMouseEvent);
@@ -240,15 +256,34 @@ static PFN_ulDestroyViewConfig         pfn_DestroyViewConfig;
 static PFN_ulVCSetIsAccelerated        pfn_VCSetIsAccelerated;
 static PFN_ulVCSetIsTransparent        pfn_VCSetIsTransparent;
 static PFN_ulVCSetInitialDeviceScale   pfn_VCSetInitialDeviceScale;
+static PFN_ulVCSetEnableImages         pfn_VCSetEnableImages;
+static PFN_ulVCSetEnableJavaScript     pfn_VCSetEnableJavaScript;
+static PFN_ulVCSetUserAgent            pfn_VCSetUserAgent;
+static PFN_ulVCSetFontFamilyStandard   pfn_VCSetFontFamilyStandard;
 static PFN_ulCreateView                pfn_CreateView;
 static PFN_ulDestroyView               pfn_DestroyView;
 static PFN_ulViewLoadHTML              pfn_ViewLoadHTML;
 static PFN_ulViewLoadURL               pfn_ViewLoadURL;
+static PFN_ulViewCanGo                 pfn_ViewCanGoBack;
+static PFN_ulViewCanGo                 pfn_ViewCanGoForward;
+static PFN_ulViewGo                    pfn_ViewGoBack;
+static PFN_ulViewGo                    pfn_ViewGoForward;
+static PFN_ulViewGo                    pfn_ViewStop;
+static PFN_ulViewSetDeviceScale        pfn_ViewSetDeviceScale;
+static PFN_ulViewResize                pfn_ViewResize;
+static PFN_ulCreateSession             pfn_CreateSession;
+static PFN_ulDestroySession            pfn_DestroySession;
 static PFN_ulViewGetSurface            pfn_ViewGetSurface;
 static PFN_ulViewFocus                 pfn_ViewFocus;
+static PFN_ulViewSetDisplayId          pfn_ViewSetDisplayId;
+static PFN_ulViewGetNeedsPaint         pfn_ViewGetNeedsPaint;
 static PFN_ulViewEvaluateScript        pfn_ViewEvaluateScript;
 static PFN_ulViewSetConsoleCallback    pfn_ViewSetConsoleCallback;
 static PFN_ulViewSetDOMReadyCallback   pfn_ViewSetDOMReadyCallback;
+static PFN_ulViewSetLoadCallback       pfn_ViewSetWindowObjectReadyCallback;
+static PFN_ulViewSetLoadCallback       pfn_ViewSetBeginLoadingCallback;
+static PFN_ulViewSetLoadCallback       pfn_ViewSetFinishLoadingCallback;
+static PFN_ulViewSetFailLoadingCallback pfn_ViewSetFailLoadingCallback;
 static PFN_ulViewFireMouseEvent        pfn_ViewFireMouseEvent;
 static PFN_ulViewFireScrollEvent       pfn_ViewFireScrollEvent;
 static PFN_ulViewFireKeyEvent          pfn_ViewFireKeyEvent;
@@ -302,6 +337,20 @@ static PFN_JSValueIsObject                     pfn_JSValueIsObject;
 
 /* ── Queue and view constants ────────────────────────────────────── */
 #define MAX_VIEWS 16
+
+/* ul_create_view* error codes (mirrored by createError in bridge.go):
+ *   -1  worker or renderer not started (ul_init not called or failed)
+ *   -2  MAX_VIEWS views already exist
+ *   -3  width or height <= 0
+ *   -11 Ultralight returned no view (usually out of GPU/system memory)
+ *   -12 out of memory copying the page content
+ *   -13 VIEW_FLAG_DISABLE_JS, but the SDK lacks ulViewConfigSetEnableJavaScript */
+#define CREATE_ERR_NOT_INIT     -1
+#define CREATE_ERR_NO_SLOT      -2
+#define CREATE_ERR_BAD_SIZE     -3
+#define CREATE_ERR_NO_VIEW      -11
+#define CREATE_ERR_OOM          -12
+#define CREATE_ERR_NO_JS_TOGGLE -13
 #define CIRC_QUEUE_INITIAL 16
 #define MOUSE_QUEUE_MAX    64
 #define SCROLL_QUEUE_MAX   16
@@ -363,6 +412,25 @@ typedef struct {
     int       bind_count;         /* how many times setup_js_bindings succeeded */
     int       msg_count_total;    /* how many messages received via goSend_dispatch */
     int       rebind_tick;        /* tick counter for spacing out binding retries */
+    /* Pause state: set from any thread, applied by the worker in ul_tick */
+    volatile bool paused;
+    bool      paused_applied;     /* display id currently reflects paused */
+    /* Dirty bounds of the last successful ul_view_copy_pixels_rgba (stats) */
+    ULIntRect last_dirty;
+    /* The last tick painted the view (ul_view_needs_paint) */
+    volatile bool painted;
+    /* Main-frame load progress (permille) and load event counters, written by
+     * the load callbacks on the worker, read by ul_view_get_load_state */
+    int       load_progress;
+    int       load_starts;
+    int       load_finishes;
+    int       load_fails;
+    /* Main-frame DOM parsed (DOMReady or FinishLoading), cleared on BeginLoading */
+    volatile bool dom_ready;
+    /* Last main-frame load failure, for ul_view_get_load_error */
+    int       load_error_code;
+    char      load_error_url[512];
+    char      load_error_desc[256];
     /* Per-view mutex: protects queue access from concurrent threads */
 #ifdef _WIN32
     CRITICAL_SECTION queue_lock;
@@ -450,7 +518,13 @@ enum CmdType {
     CMD_QUIT,
     CMD_CREATE_AND_LOAD,  /* Async: crea view + inicia carga diferida */
     CMD_CREATE_WITH_HTML, /* Sync: create + load HTML in one shot, no sleeping */
-    CMD_CREATE_WITH_URL   /* Sync: create + load URL in one shot, no sleeping */
+    CMD_CREATE_WITH_URL,  /* Sync: create + load URL in one shot, no sleeping */
+    CMD_EVAL_SYNC,        /* Evaluate JS and keep the result (ul_view_eval_sync) */
+    CMD_NAVIGATE,         /* History navigation, queries and stop (ul_view_navigate) */
+    CMD_SET_DEVICE_SCALE, /* Change a view's device scale (ul_view_set_device_scale) */
+    CMD_CREATE_SESSION,   /* Create a renderer session (ul_create_session) */
+    CMD_DESTROY_SESSION,  /* Destroy a renderer session (ul_destroy_session) */
+    CMD_RESIZE            /* Resize a view and its surface (ul_view_resize) */
 };
 
 /* ── Worker thread synchronization ────────────────────────────────── */
@@ -476,14 +550,55 @@ static volatile int g_cmd_result = 0;
 static char g_init_base_dir[PATHBUF_SIZE];
 static volatile int g_debug = 0;
 
+/* View creation flags: set by ul_set_view_flags right before a create call
+ * (Go side is blocked in send_cmd while the worker reads them). */
+#define VIEW_FLAG_DISABLE_IMAGES 0x01
+#define VIEW_FLAG_DISABLE_JS     0x02
+static volatile int g_view_flags = 0;
+
+/* Views always use Ultralight's CPU renderer, drawing into bitmap surfaces
+ * that Go copies to Ebiten textures (see ul_renderer_info). */
+#define VIEW_ACCELERATED false
+
+/* Device scale (window.devicePixelRatio) in permille for views created after
+ * ul_set_view_device_scale, set the same way as g_view_flags. */
+static volatile int g_view_scale = 1000;
+
+/* User agent for views created after ul_set_view_user_agent; empty = the
+ * Ultralight default. Set the same way as g_view_flags. */
+#define USER_AGENT_MAX 512
+static char g_view_user_agent[USER_AGENT_MAX];
+
+/* Standard font family for views created after ul_set_view_font_family;
+ * empty = the Ultralight default. Set the same way as g_view_flags. */
+#define FONT_FAMILY_MAX 256
+static char g_view_font_family[FONT_FAMILY_MAX];
+
+/* Renderer sessions (cookies, local storage) from ul_create_session. Slot 0
+ * is the default session (NULL in ulCreateView). Views are created in
+ * g_view_session, set by ul_set_view_session like g_view_flags. */
+#define MAX_SESSIONS 8
+static ULSession g_sessions[MAX_SESSIONS];
+static volatile int g_view_session = 0;
+
+static ULSession view_session(void) {
+    int sid = g_view_session;
+    return sid > 0 && sid < MAX_SESSIONS ? g_sessions[sid] : NULL;
+}
+
+/* Display id for paused views. ul_tick only refreshes display 0. */
+#define PAUSED_DISPLAY_ID 1
+
 /* ── VFS (Virtual File System) ────────────────────────────────────────── */
 #define VFS_MAX_FILES 256
 #define VFS_PATH_MAX  512
+#define VFS_MIME_MAX  128
 
 typedef struct {
     char    path[VFS_PATH_MAX];   /* normalized key (no leading /) */
     char*   data;                 /* malloc'd copy */
     size_t  size;
+    char    mime[VFS_MIME_MAX];   /* explicit content type, "" = infer from extension */
 } VfsEntry;
 
 static VfsEntry      g_vfs_files[VFS_MAX_FILES];
@@ -536,7 +651,8 @@ static const char* vfs_mime_for_ext(const char* path) {
     if (!dot) return "application/octet-stream";
     if (strcmp(dot, ".html") == 0 || strcmp(dot, ".htm") == 0) return "text/html";
     if (strcmp(dot, ".css") == 0) return "text/css";
-    if (strcmp(dot, ".js") == 0) return "application/javascript";
+    if (strcmp(dot, ".js") == 0 || strcmp(dot, ".mjs") == 0) return "application/javascript";
+    if (strcmp(dot, ".wasm") == 0) return "application/wasm";
     if (strcmp(dot, ".json") == 0) return "application/json";
     if (strcmp(dot, ".png") == 0) return "image/png";
     if (strcmp(dot, ".jpg") == 0 || strcmp(dot, ".jpeg") == 0) return "image/jpeg";
@@ -545,6 +661,7 @@ static const char* vfs_mime_for_ext(const char* path) {
     if (strcmp(dot, ".woff") == 0) return "font/woff";
     if (strcmp(dot, ".woff2") == 0) return "font/woff2";
     if (strcmp(dot, ".ttf") == 0) return "font/ttf";
+    if (strcmp(dot, ".otf") == 0) return "font/otf";
     if (strcmp(dot, ".ico") == 0) return "image/x-icon";
     if (strcmp(dot, ".xml") == 0) return "text/xml";
     if (strcmp(dot, ".txt") == 0) return "text/plain";
@@ -658,7 +775,8 @@ static bool vfs_cb_file_exists(ULString path_str) {
 static ULString vfs_cb_get_file_mime_type(ULString path_str) {
     char norm[VFS_PATH_MAX];
     vfs_extract_path(path_str, norm, VFS_PATH_MAX);
-    const char* mime = vfs_mime_for_ext(norm);
+    int idx = vfs_find(norm);
+    const char* mime = (idx >= 0 && g_vfs_files[idx].mime[0]) ? g_vfs_files[idx].mime : vfs_mime_for_ext(norm);
     blog("vfs_mime: '%s' -> '%s'", norm, mime);
     return pfn_CreateString(mime);
 }
@@ -706,18 +824,20 @@ static void console_message_cb(void* user_data, ULView caller, ULMessageSource s
     size_t len = pfn_StringGetLength(message);
     if (!data || len == 0) return;
 
-    /* Console message → console_queue */
+    /* Console message → console_queue. The first byte of each entry holds the
+     * ULMessageLevel (ul_view_get_console_message_level). */
     if (!v->console_msgs && !circ_queue_init(&v->console_msgs, &v->console_lens, &v->console_capacity)) return;
     if (v->console_count >= v->console_capacity) {
         if (!circ_queue_grow(&v->console_msgs, &v->console_lens, &v->console_capacity,
                              v->console_count, &v->console_tail, &v->console_head)) return;
     }
-    char* entry = (char*)malloc(len + 1);
+    char* entry = (char*)malloc(len + 2);
     if (!entry) return;
-    memcpy(entry, data, len);
-    entry[len] = '\0';
+    entry[0] = (char)level;
+    memcpy(entry + 1, data, len);
+    entry[len + 1] = '\0';
     v->console_msgs[v->console_head] = entry;
-    v->console_lens[v->console_head] = (int)len;
+    v->console_lens[v->console_head] = (int)len + 1;
     v->console_head = (v->console_head + 1) % v->console_capacity;
     v->console_count++;
 }
@@ -753,16 +873,42 @@ static int resolve_functions(void) {
     RESOLVE(g_hUltralight, pfn_VCSetIsAccelerated, "ulViewConfigSetIsAccelerated");
     RESOLVE(g_hUltralight, pfn_VCSetIsTransparent, "ulViewConfigSetIsTransparent");
     RESOLVE(g_hUltralight, pfn_VCSetInitialDeviceScale, "ulViewConfigSetInitialDeviceScale");
+    /* Optional: per-view image toggle (VIEW_FLAG_DISABLE_IMAGES) */
+    *(void**)&pfn_VCSetEnableImages = GETSYM(g_hUltralight, "ulViewConfigSetEnableImages");
+    /* Optional: per-view JavaScript toggle (VIEW_FLAG_DISABLE_JS) */
+    *(void**)&pfn_VCSetEnableJavaScript = GETSYM(g_hUltralight, "ulViewConfigSetEnableJavaScript");
+    *(void**)&pfn_VCSetUserAgent    = GETSYM(g_hUltralight, "ulViewConfigSetUserAgent");
+    *(void**)&pfn_VCSetFontFamilyStandard = GETSYM(g_hUltralight, "ulViewConfigSetFontFamilyStandard");
     RESOLVE(g_hUltralight, pfn_CreateView, "ulCreateView");
     RESOLVE(g_hUltralight, pfn_DestroyView, "ulDestroyView");
     RESOLVE(g_hUltralight, pfn_ViewLoadHTML, "ulViewLoadHTML");
     RESOLVE(g_hUltralight, pfn_ViewLoadURL, "ulViewLoadURL");
+    /* Optional: history navigation (ul_view_navigate) */
+    *(void**)&pfn_ViewCanGoBack    = GETSYM(g_hUltralight, "ulViewCanGoBack");
+    *(void**)&pfn_ViewCanGoForward = GETSYM(g_hUltralight, "ulViewCanGoForward");
+    *(void**)&pfn_ViewGoBack       = GETSYM(g_hUltralight, "ulViewGoBack");
+    *(void**)&pfn_ViewGoForward    = GETSYM(g_hUltralight, "ulViewGoForward");
+    *(void**)&pfn_ViewStop         = GETSYM(g_hUltralight, "ulViewStop");
+    *(void**)&pfn_ViewSetDeviceScale = GETSYM(g_hUltralight, "ulViewSetDeviceScale");
+    *(void**)&pfn_ViewResize = GETSYM(g_hUltralight, "ulViewResize");
+    *(void**)&pfn_CreateSession    = GETSYM(g_hUltralight, "ulCreateSession");
+    *(void**)&pfn_DestroySession   = GETSYM(g_hUltralight, "ulDestroySession");
     RESOLVE(g_hUltralight, pfn_ViewGetSurface, "ulViewGetSurface");
     RESOLVE(g_hUltralight, pfn_ViewFocus, "ulViewFocus");
     RESOLVE(g_hUltralight, pfn_ViewEvaluateScript, "ulViewEvaluateScript");
     RESOLVE(g_hUltralight, pfn_ViewSetConsoleCallback, "ulViewSetAddConsoleMessageCallback");
     /* Opcional: DOMReady callback para re-bind JS bindings despues de page load */
     *(void**)&pfn_ViewSetDOMReadyCallback = GETSYM(g_hUltralight, "ulViewSetAddDOMReadyCallback");
+    /* Optional: binds window.go before the page's first script runs */
+    *(void**)&pfn_ViewSetWindowObjectReadyCallback = GETSYM(g_hUltralight, "ulViewSetWindowObjectReadyCallback");
+    /* Optional: load lifecycle callbacks for ul_view_get_load_state */
+    *(void**)&pfn_ViewSetBeginLoadingCallback  = GETSYM(g_hUltralight, "ulViewSetBeginLoadingCallback");
+    *(void**)&pfn_ViewSetFinishLoadingCallback = GETSYM(g_hUltralight, "ulViewSetFinishLoadingCallback");
+    *(void**)&pfn_ViewSetFailLoadingCallback   = GETSYM(g_hUltralight, "ulViewSetFailLoadingCallback");
+    /* Optional: used to park paused views on a display that is never refreshed */
+    *(void**)&pfn_ViewSetDisplayId = GETSYM(g_hUltralight, "ulViewSetDisplayId");
+    /* Optional: which views the last tick painted, for ul_view_needs_paint */
+    *(void**)&pfn_ViewGetNeedsPaint = GETSYM(g_hUltralight, "ulViewGetNeedsPaint");
     RESOLVE(g_hUltralight, pfn_ViewFireMouseEvent, "ulViewFireMouseEvent");
     RESOLVE(g_hUltralight, pfn_ViewFireScrollEvent, "ulViewFireScrollEvent");
     RESOLVE(g_hUltralight, pfn_ViewFireKeyEvent, "ulViewFireKeyEvent");
@@ -826,6 +972,8 @@ static int resolve_functions(void) {
     blog("JSContextGetGlobalContext: %s", pfn_JSContextGetGlobalContext ? "found" : "NOT found");
     blog("JSEvaluateScript: %s", pfn_JSEvaluateScript ? "found" : "NOT found (fallback to ulViewEvaluateScript)");
     blog("DOMReadyCallback: %s", pfn_ViewSetDOMReadyCallback ? "found" : "NOT found");
+    blog("WindowObjectReadyCallback: %s", pfn_ViewSetWindowObjectReadyCallback ? "found" : "NOT found");
+    blog("LoadingCallbacks: %s", pfn_ViewSetBeginLoadingCallback ? "found" : "NOT found");
     return 0;
 }
 #undef RESOLVE
@@ -988,13 +1136,109 @@ static void dom_ready_cb(void* user_data, ULView caller, unsigned long long fram
     blog("dom_ready_cb: vid=%d fired (frame=%llu), re-binding JS", vid, frame_id);
     g_views[vid].js_bound = false;
     setup_js_bindings(vid);
+    ViewSlot* v = &g_views[vid];
+    VIEW_LOCK(v);
+    if (v->load_progress < 700) v->load_progress = 700;
+    v->dom_ready = true;
+    VIEW_UNLOCK(v);
 }
 
-/* Register DOMReady callback on a view (if available in this SDK version) */
+/* WindowObjectReady callback: fired when a new page's window object is
+ * created, before any of its scripts run. Binding here makes go.send usable
+ * from inline <head> scripts; dom_ready_cb binds again in case the page
+ * replaced window.go meanwhile. */
+static void window_object_ready_cb(void* user_data, ULView caller, unsigned long long frame_id,
+                                   bool is_main_frame, ULString url) {
+    if (!is_main_frame) return;
+    int vid = (int)(intptr_t)user_data;
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].used) return;
+    blog("window_object_ready_cb: vid=%d (frame=%llu), binding JS", vid, frame_id);
+    g_views[vid].js_bound = false;
+    setup_js_bindings(vid);
+}
+
+/* Register the DOMReady and WindowObjectReady callbacks on a view (if
+ * available in this SDK version) */
 static void register_dom_ready(int vid) {
-    if (pfn_ViewSetDOMReadyCallback && vid >= 0 && vid < MAX_VIEWS && g_views[vid].view) {
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].view) return;
+    if (pfn_ViewSetDOMReadyCallback)
         pfn_ViewSetDOMReadyCallback(g_views[vid].view, dom_ready_cb, (void*)(intptr_t)vid);
+    if (pfn_ViewSetWindowObjectReadyCallback)
+        pfn_ViewSetWindowObjectReadyCallback(g_views[vid].view, window_object_ready_cb, (void*)(intptr_t)vid);
+}
+
+/* Load callbacks: main-frame milestones become a coarse progress (Ultralight
+ * exposes no byte-level progress): 100 on begin, 700 on DOMReady, 1000 on
+ * finish or failure. The counters let Go fire OnLoadStart/OnLoadFinish; a
+ * load ends once, so a finish or failure with no load open is ignored (e.g.
+ * the cancel reported after worker_do_navigate already ended a stopped load). */
+#define LOAD_OPEN(v) ((v)->load_starts > (v)->load_finishes + (v)->load_fails)
+
+/* Copies a ULString into a NUL-terminated buffer, truncating. */
+static void copy_ulstring(ULString s, char* out, size_t out_size) {
+    const char* data = (s && pfn_StringGetData) ? pfn_StringGetData(s) : NULL;
+    size_t len = (s && pfn_StringGetLength) ? pfn_StringGetLength(s) : 0;
+    if (!data) len = 0;
+    if (len >= out_size) len = out_size - 1;
+    if (len) memcpy(out, data, len);
+    out[len] = '\0';
+}
+
+static void begin_loading_cb(void* user_data, ULView caller, unsigned long long frame_id,
+                             bool is_main_frame, ULString url) {
+    if (!is_main_frame) return;
+    int vid = (int)(intptr_t)user_data;
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].used) return;
+    ViewSlot* v = &g_views[vid];
+    VIEW_LOCK(v);
+    v->load_progress = 100;
+    v->load_starts++;
+    v->dom_ready = false;
+    VIEW_UNLOCK(v);
+}
+
+static void finish_loading_cb(void* user_data, ULView caller, unsigned long long frame_id,
+                              bool is_main_frame, ULString url) {
+    if (!is_main_frame) return;
+    int vid = (int)(intptr_t)user_data;
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].used) return;
+    ViewSlot* v = &g_views[vid];
+    VIEW_LOCK(v);
+    if (LOAD_OPEN(v)) {
+        v->load_progress = 1000;
+        v->load_finishes++;
     }
+    v->dom_ready = true;
+    VIEW_UNLOCK(v);
+}
+
+static void fail_loading_cb(void* user_data, ULView caller, unsigned long long frame_id,
+                            bool is_main_frame, ULString url, ULString description,
+                            ULString error_domain, int error_code) {
+    if (!is_main_frame) return;
+    int vid = (int)(intptr_t)user_data;
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].used) return;
+    blog("fail_loading_cb: vid=%d code=%d", vid, error_code);
+    ViewSlot* v = &g_views[vid];
+    VIEW_LOCK(v);
+    if (LOAD_OPEN(v)) {
+        v->load_progress = 1000;
+        v->load_fails++;
+        v->load_error_code = error_code;
+        copy_ulstring(url, v->load_error_url, sizeof(v->load_error_url));
+        copy_ulstring(description, v->load_error_desc, sizeof(v->load_error_desc));
+    }
+    VIEW_UNLOCK(v);
+}
+
+/* Register load callbacks on a view (if available in this SDK version) */
+static void register_load_callbacks(int vid) {
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].view) return;
+    ULView view = g_views[vid].view;
+    void* ud = (void*)(intptr_t)vid;
+    if (pfn_ViewSetBeginLoadingCallback)  pfn_ViewSetBeginLoadingCallback(view, begin_loading_cb, ud);
+    if (pfn_ViewSetFinishLoadingCallback) pfn_ViewSetFinishLoadingCallback(view, finish_loading_cb, ud);
+    if (pfn_ViewSetFailLoadingCallback)   pfn_ViewSetFailLoadingCallback(view, fail_loading_cb, ud);
 }
 
 /* Logger silencioso: descarta todos los mensajes de Ultralight */
@@ -1057,19 +1301,52 @@ static int worker_do_init(void) {
     return 0;
 }
 
+/* Builds the ULViewConfig shared by every create path, applying g_view_flags.
+ * Returns NULL if JavaScript must be disabled but the SDK can't: unlike the
+ * image toggle, that is a safety setting and is never silently ignored. */
+static ULViewConfig make_view_config(void) {
+    if ((g_view_flags & VIEW_FLAG_DISABLE_JS) && !pfn_VCSetEnableJavaScript) {
+        blog("make_view_config: ulViewConfigSetEnableJavaScript NOT found, refusing to create the view");
+        return NULL;
+    }
+    ULViewConfig vc = pfn_CreateViewConfig();
+    pfn_VCSetIsAccelerated(vc, VIEW_ACCELERATED);
+    pfn_VCSetIsTransparent(vc, true);
+    pfn_VCSetInitialDeviceScale(vc, g_view_scale / 1000.0);
+    if (g_view_flags & VIEW_FLAG_DISABLE_IMAGES) {
+        if (pfn_VCSetEnableImages) pfn_VCSetEnableImages(vc, false);
+        else blog("make_view_config: ulViewConfigSetEnableImages NOT found, images stay enabled");
+    }
+    if (g_view_flags & VIEW_FLAG_DISABLE_JS) pfn_VCSetEnableJavaScript(vc, false);
+    if (g_view_user_agent[0]) {
+        if (pfn_VCSetUserAgent) {
+            ULString ua = pfn_CreateString(g_view_user_agent);
+            pfn_VCSetUserAgent(vc, ua);
+            pfn_DestroyString(ua);
+        } else blog("make_view_config: ulViewConfigSetUserAgent NOT found, default user agent");
+    }
+    if (g_view_font_family[0]) {
+        if (pfn_VCSetFontFamilyStandard) {
+            ULString f = pfn_CreateString(g_view_font_family);
+            pfn_VCSetFontFamilyStandard(vc, f);
+            pfn_DestroyString(f);
+        } else blog("make_view_config: ulViewConfigSetFontFamilyStandard NOT found, default font");
+    }
+    return vc;
+}
+
 static int worker_do_create_view(int width, int height) {
     int vid;
     for (vid = 0; vid < MAX_VIEWS; vid++)
         if (!g_views[vid].used) break;
-    if (vid >= MAX_VIEWS) { blog("worker_do_create_view: no slot"); return -1; }
+    if (!g_renderer) { blog("worker_do_create_view: renderer NULL"); return CREATE_ERR_NOT_INIT; }
+    if (vid >= MAX_VIEWS) { blog("worker_do_create_view: no slot"); return CREATE_ERR_NO_SLOT; }
     ViewSlot* v = &g_views[vid];
-    ULViewConfig vc = pfn_CreateViewConfig();
-    pfn_VCSetIsAccelerated(vc, false);
-    pfn_VCSetIsTransparent(vc, true);
-    pfn_VCSetInitialDeviceScale(vc, 1.0);
-    v->view = pfn_CreateView(g_renderer, (unsigned int)width, (unsigned int)height, vc, NULL);
+    ULViewConfig vc = make_view_config();
+    if (!vc) return CREATE_ERR_NO_JS_TOGGLE;
+    v->view = pfn_CreateView(g_renderer, (unsigned int)width, (unsigned int)height, vc, view_session());
     pfn_DestroyViewConfig(vc);
-    if (!v->view) { blog("worker_do_create_view: view NULL"); return -11; }
+    if (!v->view) { blog("worker_do_create_view: view NULL"); return CREATE_ERR_NO_VIEW; }
     v->surface = pfn_ViewGetSurface(v->view);
     v->width = width;
     v->height = height;
@@ -1090,9 +1367,19 @@ static int worker_do_create_view(int width, int height) {
     v->bind_count = 0;
     v->msg_count_total = 0;
     v->rebind_tick = 0;
+    v->paused = false;
+    v->paused_applied = false;
+    v->painted = true;
+    memset(&v->last_dirty, 0, sizeof(v->last_dirty));
+    v->load_progress = 0;
+    v->load_starts = v->load_finishes = v->load_fails = 0;
+    v->dom_ready = false;
+    v->load_error_code = 0;
+    v->load_error_url[0] = v->load_error_desc[0] = '\0';
     VIEW_LOCK_INIT(v);
     pfn_ViewSetConsoleCallback(v->view, console_message_cb, (void*)(intptr_t)vid);
     register_dom_ready(vid);
+    register_load_callbacks(vid);
     pfn_ViewFocus(v->view);
     g_view_count++;
     /* Single update cycle, no sleeping — pfn_Update processes synchronously */
@@ -1157,15 +1444,14 @@ static int worker_do_create_and_load(int width, int height, const char* str, boo
     int vid;
     for (vid = 0; vid < MAX_VIEWS; vid++)
         if (!g_views[vid].used) break;
-    if (vid >= MAX_VIEWS) { blog("worker_do_create_and_load: no slot"); return -1; }
+    if (!g_renderer) { blog("worker_do_create_and_load: renderer NULL"); return CREATE_ERR_NOT_INIT; }
+    if (vid >= MAX_VIEWS) { blog("worker_do_create_and_load: no slot"); return CREATE_ERR_NO_SLOT; }
     ViewSlot* v = &g_views[vid];
-    ULViewConfig vc = pfn_CreateViewConfig();
-    pfn_VCSetIsAccelerated(vc, false);
-    pfn_VCSetIsTransparent(vc, true);
-    pfn_VCSetInitialDeviceScale(vc, 1.0);
-    v->view = pfn_CreateView(g_renderer, (unsigned int)width, (unsigned int)height, vc, NULL);
+    ULViewConfig vc = make_view_config();
+    if (!vc) return CREATE_ERR_NO_JS_TOGGLE;
+    v->view = pfn_CreateView(g_renderer, (unsigned int)width, (unsigned int)height, vc, view_session());
     pfn_DestroyViewConfig(vc);
-    if (!v->view) { blog("worker_do_create_and_load: view NULL"); return -11; }
+    if (!v->view) { blog("worker_do_create_and_load: view NULL"); return CREATE_ERR_NO_VIEW; }
     v->surface = pfn_ViewGetSurface(v->view);
     v->width = width;
     v->height = height;
@@ -1182,8 +1468,18 @@ static int worker_do_create_and_load(int width, int height, const char* str, boo
     v->bind_count = 0;
     v->msg_count_total = 0;
     v->rebind_tick = 0;
+    v->paused = false;
+    v->paused_applied = false;
+    v->painted = true;
+    memset(&v->last_dirty, 0, sizeof(v->last_dirty));
+    v->load_progress = 0;
+    v->load_starts = v->load_finishes = v->load_fails = 0;
+    v->dom_ready = false;
+    v->load_error_code = 0;
+    v->load_error_url[0] = v->load_error_desc[0] = '\0';
     pfn_ViewSetConsoleCallback(v->view, console_message_cb, (void*)(intptr_t)vid);
     register_dom_ready(vid);
+    register_load_callbacks(vid);
     pfn_ViewFocus(v->view);
     VIEW_LOCK_INIT(v);
     g_view_count++;
@@ -1196,7 +1492,7 @@ static int worker_do_create_and_load(int width, int height, const char* str, boo
         v->used = false;
         VIEW_LOCK_DESTROY(v);
         g_view_count--;
-        return -12;
+        return CREATE_ERR_OOM;
     }
     v->pending_is_url = is_url;
     v->load_phase = 1; /* priming */
@@ -1211,15 +1507,14 @@ static int worker_do_create_with_content(int width, int height, const char* cont
     int vid;
     for (vid = 0; vid < MAX_VIEWS; vid++)
         if (!g_views[vid].used) break;
-    if (vid >= MAX_VIEWS) { blog("worker_do_create_with_content: no slot"); return -1; }
+    if (!g_renderer) { blog("worker_do_create_with_content: renderer NULL"); return CREATE_ERR_NOT_INIT; }
+    if (vid >= MAX_VIEWS) { blog("worker_do_create_with_content: no slot"); return CREATE_ERR_NO_SLOT; }
     ViewSlot* v = &g_views[vid];
-    ULViewConfig vc = pfn_CreateViewConfig();
-    pfn_VCSetIsAccelerated(vc, false);
-    pfn_VCSetIsTransparent(vc, true);
-    pfn_VCSetInitialDeviceScale(vc, 1.0);
-    v->view = pfn_CreateView(g_renderer, (unsigned int)width, (unsigned int)height, vc, NULL);
+    ULViewConfig vc = make_view_config();
+    if (!vc) return CREATE_ERR_NO_JS_TOGGLE;
+    v->view = pfn_CreateView(g_renderer, (unsigned int)width, (unsigned int)height, vc, view_session());
     pfn_DestroyViewConfig(vc);
-    if (!v->view) { blog("worker_do_create_with_content: view NULL"); return -11; }
+    if (!v->view) { blog("worker_do_create_with_content: view NULL"); return CREATE_ERR_NO_VIEW; }
     v->surface = pfn_ViewGetSurface(v->view);
     v->width = width;
     v->height = height;
@@ -1236,12 +1531,22 @@ static int worker_do_create_with_content(int width, int height, const char* cont
     v->bind_count = 0;
     v->msg_count_total = 0;
     v->rebind_tick = 0;
+    v->paused = false;
+    v->paused_applied = false;
+    v->painted = true;
+    memset(&v->last_dirty, 0, sizeof(v->last_dirty));
+    v->load_progress = 0;
+    v->load_starts = v->load_finishes = v->load_fails = 0;
+    v->dom_ready = false;
+    v->load_error_code = 0;
+    v->load_error_url[0] = v->load_error_desc[0] = '\0';
     v->load_phase = 0;
     v->phase_counter = 0;
     v->pending_load_str = NULL;
     VIEW_LOCK_INIT(v);
     pfn_ViewSetConsoleCallback(v->view, console_message_cb, (void*)(intptr_t)vid);
     register_dom_ready(vid);
+    register_load_callbacks(vid);
     pfn_ViewFocus(v->view);
     g_view_count++;
     /* Load content immediately */
@@ -1261,6 +1566,174 @@ static int worker_do_create_with_content(int width, int height, const char* cont
     return vid;
 }
 
+/* Snapshot the view's JS eval queue under lock and run it (worker thread).
+ * Used by ul_tick and before EvalSync, so a sync eval sees earlier Evals. */
+static void run_js_queue(ViewSlot* v) {
+    char** local_js = NULL;
+    int local_js_count = 0;
+    VIEW_LOCK(v);
+    if (v->js_queue && v->js_count > 0) {
+        local_js_count = v->js_count;
+        local_js = (char**)malloc(sizeof(char*) * local_js_count);
+        if (local_js) {
+            memcpy(local_js, v->js_queue, sizeof(char*) * local_js_count);
+            /* Clear originals so they won't be double-freed */
+            memset(v->js_queue, 0, sizeof(char*) * local_js_count);
+        } else {
+            local_js_count = 0;
+        }
+    }
+    v->js_count = 0;
+    VIEW_UNLOCK(v);
+    if (!local_js) return;
+    for (int i = 0; i < local_js_count; i++) {
+        if (!local_js[i]) continue;
+        ULString s = pfn_CreateString(local_js[i]);
+        pfn_ViewEvaluateScript(v->view, s, NULL);
+        pfn_DestroyString(s);
+        free(local_js[i]);
+    }
+    free(local_js);
+}
+
+/* EvalSync result, owned by the bridge until the next eval (see ul_view_eval_sync). */
+static char* g_eval_result = NULL;
+static int   g_eval_result_len = 0;
+
+/* Copies a ULString into g_eval_result (replacing the previous one). */
+static void set_eval_result(ULString s) {
+    free(g_eval_result);
+    g_eval_result = NULL;
+    g_eval_result_len = 0;
+    const char* data = (s && pfn_StringGetData) ? pfn_StringGetData(s) : NULL;
+    size_t len = (s && pfn_StringGetLength) ? pfn_StringGetLength(s) : 0;
+    if (len > INT_MAX - 1) len = INT_MAX - 1;
+    g_eval_result = (char*)malloc(len + 1);
+    if (!g_eval_result) return;
+    if (data && len) memcpy(g_eval_result, data, len);
+    g_eval_result[len] = '\0';
+    g_eval_result_len = (int)len;
+}
+
+/* Evaluates js in the view and stores the result as a string.
+ * Returns 0 on success, 1 if the script threw (result holds the exception
+ * message), -1 if the view doesn't exist, -2 if it is still loading. */
+static int worker_do_eval_sync(int vid, const char* js) {
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].used || !g_views[vid].view) return -1;
+    ViewSlot* v = &g_views[vid];
+    if (v->load_phase != 0) return -2;
+    run_js_queue(v);
+    ULString script = pfn_CreateString(js ? js : "");
+    ULString exception = NULL;
+    /* result and exception are owned by the view: copy, don't destroy */
+    ULString result = pfn_ViewEvaluateScript(v->view, script, &exception);
+    pfn_DestroyString(script);
+    bool threw = exception && pfn_StringGetLength && pfn_StringGetLength(exception) > 0;
+    set_eval_result(threw ? exception : result);
+    return threw ? 1 : 0;
+}
+
+/* Navigation ops for ul_view_navigate (NAV_* in nav.go) */
+enum { NAV_BACK = 0, NAV_FORWARD, NAV_CAN_BACK, NAV_CAN_FORWARD, NAV_STOP };
+
+/* WebKit's code for a cancelled load, reported for stopped loads */
+#define LOAD_ERROR_CANCELLED (-999)
+
+/* Runs a history op. Queries return 1/0; the others return 0, or -3 if this
+ * SDK lacks the API. The page load itself happens in later ticks, like a
+ * link click (DOMReady re-binds JS). */
+static int worker_do_navigate(int vid, int op) {
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].used || !g_views[vid].view) return -1;
+    ULView view = g_views[vid].view;
+    switch (op) {
+    case NAV_BACK:
+        if (!pfn_ViewGoBack) return -3;
+        pfn_ViewGoBack(view);
+        return 0;
+    case NAV_FORWARD:
+        if (!pfn_ViewGoForward) return -3;
+        pfn_ViewGoForward(view);
+        return 0;
+    case NAV_CAN_BACK:
+        return pfn_ViewCanGoBack && pfn_ViewCanGoBack(view) ? 1 : 0;
+    case NAV_CAN_FORWARD:
+        return pfn_ViewCanGoForward && pfn_ViewCanGoForward(view) ? 1 : 0;
+    case NAV_STOP: {
+        if (!pfn_ViewStop) return -3;
+        pfn_ViewStop(view);
+        /* Not every SDK reports the cancel through FailLoading: end the load
+         * here so Go sees a failure either way. */
+        ViewSlot* v = &g_views[vid];
+        VIEW_LOCK(v);
+        if (LOAD_OPEN(v)) {
+            v->load_progress = 1000;
+            v->load_fails++;
+            v->load_error_code = LOAD_ERROR_CANCELLED;
+            v->load_error_url[0] = '\0';
+            snprintf(v->load_error_desc, sizeof(v->load_error_desc), "Load stopped");
+        }
+        VIEW_UNLOCK(v);
+        return 0;
+    }
+    }
+    return -1;
+}
+
+/* Sets the view's device scale (permille). The page sees the new
+ * devicePixelRatio and re-evaluates resolution media queries. -3 if the SDK
+ * lacks ulViewSetDeviceScale. */
+static int worker_do_set_device_scale(int vid, int permille) {
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].used || !g_views[vid].view) return -1;
+    if (!pfn_ViewSetDeviceScale) return -3;
+    if (permille <= 0) return -1;
+    pfn_ViewSetDeviceScale(g_views[vid].view, permille / 1000.0);
+    return 0;
+}
+
+/* Resizes the view; the page re-lays out and its next paint covers the new
+ * surface. -3 if the SDK lacks ulViewResize. */
+static int worker_do_resize(int vid, int width, int height) {
+    if (vid < 0 || vid >= MAX_VIEWS || !g_views[vid].used || !g_views[vid].view) return -1;
+    if (!pfn_ViewResize) return -3;
+    if (width <= 0 || height <= 0) return -1;
+    ViewSlot* v = &g_views[vid];
+    pfn_ViewResize(v->view, (unsigned int)width, (unsigned int)height);
+    v->surface = pfn_ViewGetSurface(v->view);
+    v->width = width;
+    v->height = height;
+    v->surface_width = v->surface ? (int)pfn_SurfaceGetWidth(v->surface) : width;
+    v->surface_height = v->surface ? (int)pfn_SurfaceGetHeight(v->surface) : height;
+    memset(&v->last_dirty, 0, sizeof(v->last_dirty));
+    v->painted = true;
+    return 0;
+}
+
+/* Creates a session with its own cookies and local storage. Persistent
+ * sessions keep them on disk under name in the cache path. Returns the
+ * session id (>= 1), -1 without renderer, -2 if all slots are used, -3 if the
+ * SDK lacks ulCreateSession, -11 if Ultralight returned no session. */
+static int worker_do_create_session(const char* name, bool persistent) {
+    if (!g_renderer) return -1;
+    if (!pfn_CreateSession || !pfn_DestroySession) return -3;
+    int sid;
+    for (sid = 1; sid < MAX_SESSIONS; sid++)
+        if (!g_sessions[sid]) break;
+    if (sid >= MAX_SESSIONS) { blog("worker_do_create_session: no slot"); return -2; }
+    ULString s = pfn_CreateString(name ? name : "");
+    g_sessions[sid] = pfn_CreateSession(g_renderer, persistent, s);
+    pfn_DestroyString(s);
+    if (!g_sessions[sid]) { blog("worker_do_create_session: session NULL"); return -11; }
+    return sid;
+}
+
+/* Destroys a session. Go closes the session's views first. */
+static int worker_do_destroy_session(int sid) {
+    if (sid <= 0 || sid >= MAX_SESSIONS || !g_sessions[sid]) return -1;
+    pfn_DestroySession(g_sessions[sid]);
+    g_sessions[sid] = NULL;
+    return 0;
+}
+
 static void worker_do_tick(void) {
     /* Process views in async loading state */
     for (int vid = 0; vid < MAX_VIEWS; vid++) {
@@ -1294,6 +1767,16 @@ static void worker_do_tick(void) {
         ViewSlot* v = &g_views[vid];
         if (!v->used || !v->view) continue;
 
+        /* Paused views are parked on a display that ul_tick never refreshes, so
+         * requestAnimationFrame and CSS animations stop. Their input and JS queues
+         * are left untouched and processed on resume. */
+        if (v->paused != v->paused_applied) {
+            if (pfn_ViewSetDisplayId)
+                pfn_ViewSetDisplayId(v->view, v->paused ? PAUSED_DISPLAY_ID : 0);
+            v->paused_applied = v->paused;
+        }
+        if (v->paused) continue;
+
         /* Snapshot queues under lock, then process without holding it */
         MouseQueueEntry  local_mouse[MOUSE_QUEUE_MAX];
         ScrollQueueEntry local_scroll[SCROLL_QUEUE_MAX];
@@ -1343,11 +1826,9 @@ static void worker_do_tick(void) {
             pfn_ViewFireScrollEvent(v->view, evt);
             pfn_DestroyScrollEvent(evt);
         }
-        /* Snapshot key, JS y binary queues under lock */
+        /* Snapshot key y binary queues under lock (JS queue: run_js_queue) */
         KeyQueueEntry local_keys[KEY_QUEUE_MAX];
         int local_key_count;
-        char** local_js = NULL;
-        int local_js_count = 0;
         BinaryQueueEntry* local_binary = NULL;
         int local_binary_count = 0;
 
@@ -1356,18 +1837,6 @@ static void worker_do_tick(void) {
         if (local_key_count > 0)
             memcpy(local_keys, v->key_queue, sizeof(KeyQueueEntry) * local_key_count);
         v->key_count = 0;
-        if (v->js_queue && v->js_count > 0) {
-            local_js_count = v->js_count;
-            local_js = (char**)malloc(sizeof(char*) * local_js_count);
-            if (local_js) {
-                memcpy(local_js, v->js_queue, sizeof(char*) * local_js_count);
-                /* Clear originals so they won't be double-freed */
-                memset(v->js_queue, 0, sizeof(char*) * local_js_count);
-            } else {
-                local_js_count = 0;
-            }
-        }
-        v->js_count = 0;
         if (v->binary_queue && v->binary_count > 0) {
             local_binary_count = v->binary_count;
             local_binary = (BinaryQueueEntry*)malloc(sizeof(BinaryQueueEntry) * local_binary_count);
@@ -1431,17 +1900,8 @@ static void worker_do_tick(void) {
             SetKeyboardState(saved_ks);
         }
 #endif
-        /* Process JS eval queue (already snapshotted above) */
-        if (local_js) {
-            for (int i = 0; i < local_js_count; i++) {
-                if (!local_js[i]) continue;
-                ULString s = pfn_CreateString(local_js[i]);
-                pfn_ViewEvaluateScript(v->view, s, NULL);
-                pfn_DestroyString(s);
-                free(local_js[i]);
-            }
-            free(local_js);
-        }
+        /* Process JS eval queue */
+        run_js_queue(v);
 
         /* Process binary queue (zero-copy send a window.go.receive). Para cada
          * entry:
@@ -1553,7 +2013,7 @@ static void worker_do_tick(void) {
      * Retry every 60 ticks (~1s at 60fps), max 10 attempts. */
     for (int vid = 0; vid < MAX_VIEWS; vid++) {
         ViewSlot* v = &g_views[vid];
-        if (!v->used || !v->view || v->load_phase != 0) continue;
+        if (!v->used || !v->view || v->load_phase != 0 || v->paused) continue;
         if (v->msg_count_total > 0) continue; /* bindings work, stop retrying */
         v->rebind_tick++;
         if (v->bind_count > 0 && v->bind_count < 10 && v->rebind_tick % 60 == 0) {
@@ -1562,13 +2022,20 @@ static void worker_do_tick(void) {
     }
     pfn_Update(g_renderer);
     if (pfn_RefreshDisplay) pfn_RefreshDisplay(g_renderer, 0);
+    /* A view painted by this tick (animation, timer, input) will likely paint
+     * again next tick. Without ulViewGetNeedsPaint, assume it does. */
+    for (int vid = 0; vid < MAX_VIEWS; vid++) {
+        ViewSlot* v = &g_views[vid];
+        if (!v->used || !v->view) continue;
+        v->painted = !v->paused && (!pfn_ViewGetNeedsPaint || pfn_ViewGetNeedsPaint(v->view));
+    }
     pfn_Render(g_renderer);
 }
 
-/* ── send_cmd / worker_thread_proc (platform-specific) ───────────── */
+/* ── send_cmd_raw / worker_thread_proc (platform-specific) ───────── */
 #ifdef _WIN32
 
-static void send_cmd(enum CmdType cmd, const char* str_arg, int i1, int i2) {
+static void send_cmd_raw(enum CmdType cmd, const char* str_arg, int i1, int i2) {
     g_cmd_str_arg = str_arg;
     g_cmd_int1 = i1;
     g_cmd_int2 = i2;
@@ -1612,9 +2079,29 @@ static DWORD WINAPI worker_thread_proc(LPVOID param) {
         case CMD_TICK:
             worker_do_tick();
             break;
+        case CMD_EVAL_SYNC:
+            g_cmd_result = worker_do_eval_sync(g_cmd_int1, str_arg);
+            break;
+        case CMD_NAVIGATE:
+            g_cmd_result = worker_do_navigate(g_cmd_int1, g_cmd_int2);
+            break;
+        case CMD_SET_DEVICE_SCALE:
+            g_cmd_result = worker_do_set_device_scale(g_cmd_int1, g_cmd_int2);
+            break;
+        case CMD_RESIZE:
+            g_cmd_result = worker_do_resize(g_cmd_int1, g_cmd_int2 >> 16, g_cmd_int2 & 0xFFFF);
+            break;
+        case CMD_CREATE_SESSION:
+            g_cmd_result = worker_do_create_session(str_arg, g_cmd_int1 != 0);
+            break;
+        case CMD_DESTROY_SESSION:
+            g_cmd_result = worker_do_destroy_session(g_cmd_int1);
+            break;
         case CMD_QUIT:
             for (int i = 0; i < MAX_VIEWS; i++)
                 worker_do_destroy_view(i);
+            for (int i = 1; i < MAX_SESSIONS; i++)
+                worker_do_destroy_session(i);
             if (g_renderer) { pfn_DestroyRenderer((ULRenderer)g_renderer); g_renderer = NULL; }
             SetEvent(g_done_event);
             return 0;
@@ -1628,7 +2115,7 @@ static DWORD WINAPI worker_thread_proc(LPVOID param) {
 
 #else /* POSIX (Linux, macOS) */
 
-static void send_cmd(enum CmdType cmd, const char* str_arg, int i1, int i2) {
+static void send_cmd_raw(enum CmdType cmd, const char* str_arg, int i1, int i2) {
     pthread_mutex_lock(&g_cmd_mutex);
     g_cmd_str_arg = str_arg;
     g_cmd_int1 = i1;
@@ -1682,9 +2169,29 @@ static void* worker_thread_proc(void* param) {
         case CMD_TICK:
             worker_do_tick();
             break;
+        case CMD_EVAL_SYNC:
+            g_cmd_result = worker_do_eval_sync(g_cmd_int1, str_arg);
+            break;
+        case CMD_NAVIGATE:
+            g_cmd_result = worker_do_navigate(g_cmd_int1, g_cmd_int2);
+            break;
+        case CMD_SET_DEVICE_SCALE:
+            g_cmd_result = worker_do_set_device_scale(g_cmd_int1, g_cmd_int2);
+            break;
+        case CMD_RESIZE:
+            g_cmd_result = worker_do_resize(g_cmd_int1, g_cmd_int2 >> 16, g_cmd_int2 & 0xFFFF);
+            break;
+        case CMD_CREATE_SESSION:
+            g_cmd_result = worker_do_create_session(str_arg, g_cmd_int1 != 0);
+            break;
+        case CMD_DESTROY_SESSION:
+            g_cmd_result = worker_do_destroy_session(g_cmd_int1);
+            break;
         case CMD_QUIT:
             for (int i = 0; i < MAX_VIEWS; i++)
                 worker_do_destroy_view(i);
+            for (int i = 1; i < MAX_SESSIONS; i++)
+                worker_do_destroy_session(i);
             if (g_renderer) { pfn_DestroyRenderer((ULRenderer)g_renderer); g_renderer = NULL; }
             pthread_mutex_lock(&g_cmd_mutex);
             g_cmd_done = 1;
@@ -1705,6 +2212,32 @@ static void* worker_thread_proc(void* param) {
 
 #endif /* _WIN32 / POSIX */
 
+/* send_cmd: runs one command on the worker thread and returns g_cmd_result.
+ * g_cmd_* hold a single command, so concurrent callers (e.g. Update and Eval
+ * from different goroutines) are serialized here; the result is read before
+ * the next caller can overwrite it. */
+#ifdef _WIN32
+static SRWLOCK g_send_lock = SRWLOCK_INIT;
+#else
+static pthread_mutex_t g_send_lock = PTHREAD_MUTEX_INITIALIZER;
+#endif
+
+static int send_cmd(enum CmdType cmd, const char* str_arg, int i1, int i2) {
+#ifdef _WIN32
+    AcquireSRWLockExclusive(&g_send_lock);
+#else
+    pthread_mutex_lock(&g_send_lock);
+#endif
+    send_cmd_raw(cmd, str_arg, i1, i2);
+    int rc = g_cmd_result;
+#ifdef _WIN32
+    ReleaseSRWLockExclusive(&g_send_lock);
+#else
+    pthread_mutex_unlock(&g_send_lock);
+#endif
+    return rc;
+}
+
 /* ── SDK library loading ──────────────────────────────────────────── */
 #ifdef _WIN32
 
@@ -1760,9 +2293,38 @@ static int load_sdk_libs(const char* base_dir) {
 
 #endif /* _WIN32 / POSIX */
 
+/* Unloads the SDK libraries a failed ul_init left loaded, so it can be retried
+ * with another base_dir. */
+static void unload_sdk_libs(void) {
+#ifdef _WIN32
+    if (g_hAppCore) FreeLibrary(g_hAppCore);
+    if (g_hUltralight) FreeLibrary(g_hUltralight);
+    if (g_hWebCore) FreeLibrary(g_hWebCore);
+    if (g_hUltralightCore) FreeLibrary(g_hUltralightCore);
+#else
+    if (g_hAppCore) dlclose(g_hAppCore);
+    if (g_hUltralight) dlclose(g_hUltralight);
+    if (g_hWebCore) dlclose(g_hWebCore);
+    if (g_hUltralightCore) dlclose(g_hUltralightCore);
+#endif
+    g_hAppCore = g_hUltralight = g_hWebCore = g_hUltralightCore = NULL;
+}
+
 /* ── Exported functions for Go ────────────────────────────────────── */
+/* Bridge build version (BridgeVersion in Go): bump the minor version when
+ * exports are added, so callers can check for the features they need. */
+#define UL_BRIDGE_VERSION "1.16.0"
+
+EXPORT const char* ul_bridge_version(void) {
+    return UL_BRIDGE_VERSION;
+}
+
+/* Loads the SDK from base_dir and starts the worker and renderer. Returns 0
+ * or a negative code. A failed call can be retried (e.g. with another
+ * base_dir); Go stops calling it after the first success. */
 EXPORT int ul_init(const char* base_dir, int debug) {
     g_debug = debug;
+    if (g_log) { fclose(g_log); g_log = NULL; }
     if (g_debug) {
         char logname[PATHBUF_SIZE];
         snprintf(logname, PATHBUF_SIZE, "%s%cbridge.log", base_dir, PATH_SEP);
@@ -1771,43 +2333,57 @@ EXPORT int ul_init(const char* base_dir, int debug) {
     blog("ul_init: base_dir='%s' debug=%d", base_dir, debug);
 
 #ifdef _WIN32
-    AddVectoredExceptionHandler(1, msvc_veh_handler);
-    AddVectoredContinueHandler(1, msvc_vch_handler);
+    static bool handlers_added = false;
+    if (!handlers_added) {
+        AddVectoredExceptionHandler(1, msvc_veh_handler);
+        AddVectoredContinueHandler(1, msvc_vch_handler);
+        handlers_added = true;
+    }
 #endif
 
-    int rc = load_sdk_libs(base_dir);
-    if (rc != 0) return rc;
-    rc = resolve_functions();
-    if (rc != 0) { blog("FAIL: resolve rc=%d", rc); return rc; }
+    /* A retry after the worker's renderer init failed keeps the libraries
+     * that already loaded and resolved. */
+    if (!g_hAppCore) {
+        int rc = load_sdk_libs(base_dir);
+        if (rc == 0) {
+            rc = resolve_functions();
+            if (rc != 0) blog("FAIL: resolve rc=%d", rc);
+        }
+        if (rc != 0) { unload_sdk_libs(); return rc; }
+    }
     blog("ul_init: Ultralight %s", pfn_VersionString());
     strncpy(g_init_base_dir, base_dir, PATHBUF_SIZE - 1);
     g_init_base_dir[PATHBUF_SIZE - 1] = '\0';
 
 #ifdef _WIN32
-    g_cmd_event = CreateEventA(NULL, FALSE, FALSE, NULL);
-    g_done_event = CreateEventA(NULL, FALSE, FALSE, NULL);
-    g_worker_thread = CreateThread(NULL, 0, worker_thread_proc, NULL, 0, NULL);
-    if (!g_worker_thread) { blog("FAIL: CreateThread"); return -20; }
+    if (!g_worker_thread) {
+        if (!g_cmd_event) g_cmd_event = CreateEventA(NULL, FALSE, FALSE, NULL);
+        if (!g_done_event) g_done_event = CreateEventA(NULL, FALSE, FALSE, NULL);
+        g_worker_thread = CreateThread(NULL, 0, worker_thread_proc, NULL, 0, NULL);
+        if (!g_worker_thread) { blog("FAIL: CreateThread"); return -20; }
+    }
 #else
-    int pt_rc = pthread_create(&g_worker_thread, NULL, worker_thread_proc, NULL);
-    if (pt_rc != 0) { blog("FAIL: pthread_create rc=%d", pt_rc); return -20; }
-    g_worker_started = 1;
+    if (!g_worker_started) {
+        int pt_rc = pthread_create(&g_worker_thread, NULL, worker_thread_proc, NULL);
+        if (pt_rc != 0) { blog("FAIL: pthread_create rc=%d", pt_rc); return -20; }
+        g_worker_started = 1;
+    }
 #endif
 
-    send_cmd(CMD_INIT, NULL, 0, 0);
-    if (g_cmd_result != 0) { blog("FAIL: worker init rc=%d", g_cmd_result); return g_cmd_result; }
+    int init_rc = send_cmd(CMD_INIT, NULL, 0, 0);
+    if (init_rc != 0) { blog("FAIL: worker init rc=%d", init_rc); return init_rc; }
     blog("ul_init: OK");
     return 0;
 }
 
 EXPORT int ul_create_view(int width, int height) {
 #ifdef _WIN32
-    if (!g_worker_thread) return -1;
+    if (!g_worker_thread) return CREATE_ERR_NOT_INIT;
 #else
-    if (!g_worker_started) return -1;
+    if (!g_worker_started) return CREATE_ERR_NOT_INIT;
 #endif
-    send_cmd(CMD_CREATE_VIEW, NULL, width, height);
-    return g_cmd_result;
+    if (width <= 0 || height <= 0) return CREATE_ERR_BAD_SIZE;
+    return send_cmd(CMD_CREATE_VIEW, NULL, width, height);
 }
 
 EXPORT void ul_destroy_view(int view_id) {
@@ -1843,36 +2419,97 @@ EXPORT void ul_view_load_url(int view_id, const char* url) {
  * Usar ul_view_is_ready para saber cuando esta lista. */
 EXPORT int ul_create_view_async(int width, int height, const char* url) {
 #ifdef _WIN32
-    if (!url || !g_worker_thread) return -1;
+    if (!url || !g_worker_thread) return CREATE_ERR_NOT_INIT;
 #else
-    if (!url || !g_worker_started) return -1;
+    if (!url || !g_worker_started) return CREATE_ERR_NOT_INIT;
 #endif
-    send_cmd(CMD_CREATE_AND_LOAD, url, width, height);
-    return g_cmd_result;
+    if (width <= 0 || height <= 0) return CREATE_ERR_BAD_SIZE;
+    return send_cmd(CMD_CREATE_AND_LOAD, url, width, height);
 }
 
 /* Fast sync create + load HTML: one worker roundtrip, no sleeping.
  * Returns view_id (>= 0) or negative on error. */
 EXPORT int ul_create_view_with_html(int width, int height, const char* html) {
 #ifdef _WIN32
-    if (!g_worker_thread) return -1;
+    if (!g_worker_thread) return CREATE_ERR_NOT_INIT;
 #else
-    if (!g_worker_started) return -1;
+    if (!g_worker_started) return CREATE_ERR_NOT_INIT;
 #endif
-    send_cmd(CMD_CREATE_WITH_HTML, html ? html : "", width, height);
-    return g_cmd_result;
+    if (width <= 0 || height <= 0) return CREATE_ERR_BAD_SIZE;
+    return send_cmd(CMD_CREATE_WITH_HTML, html ? html : "", width, height);
 }
 
 /* Fast sync create + load URL: one worker roundtrip, no sleeping.
  * Returns view_id (>= 0) or negative on error. */
 EXPORT int ul_create_view_with_url(int width, int height, const char* url) {
 #ifdef _WIN32
-    if (!url || !g_worker_thread) return -1;
+    if (!url || !g_worker_thread) return CREATE_ERR_NOT_INIT;
 #else
-    if (!url || !g_worker_started) return -1;
+    if (!url || !g_worker_started) return CREATE_ERR_NOT_INIT;
 #endif
-    send_cmd(CMD_CREATE_WITH_URL, url, width, height);
-    return g_cmd_result;
+    if (width <= 0 || height <= 0) return CREATE_ERR_BAD_SIZE;
+    return send_cmd(CMD_CREATE_WITH_URL, url, width, height);
+}
+
+/* Sets the flags (VIEW_FLAG_*) applied to views created after this call.
+ * Go calls it before every create so each view gets its own Options. */
+EXPORT void ul_set_view_flags(int flags) {
+    g_view_flags = flags;
+}
+
+/* Sets the session (id from ul_create_session, 0 = default) of views created
+ * after this call. Go calls it before every create, like ul_set_view_flags. */
+EXPORT void ul_set_view_session(int session_id) {
+    g_view_session = session_id;
+}
+
+/* Creates a renderer session on the worker; see worker_do_create_session. */
+EXPORT int ul_create_session(const char* name, int persistent) {
+#ifdef _WIN32
+    if (!g_worker_thread) return -1;
+#else
+    if (!g_worker_started) return -1;
+#endif
+    return send_cmd(CMD_CREATE_SESSION, name ? name : "", persistent, 0);
+}
+
+/* Destroys a session created with ul_create_session. */
+EXPORT int ul_destroy_session(int session_id) {
+#ifdef _WIN32
+    if (!g_worker_thread) return -1;
+#else
+    if (!g_worker_started) return -1;
+#endif
+    return send_cmd(CMD_DESTROY_SESSION, NULL, session_id, 0);
+}
+
+/* Sets the user agent (navigator.userAgent and the User-Agent header) of views
+ * created after this call; NULL or "" restores the Ultralight default. Go
+ * calls it before every create, like ul_set_view_flags. */
+EXPORT void ul_set_view_user_agent(const char* ua) {
+    snprintf(g_view_user_agent, USER_AGENT_MAX, "%s", ua ? ua : "");
+}
+
+/* Sets the standard font family (used by text with no font-family) of views
+ * created after this call; NULL or "" restores the Ultralight default. Go
+ * calls it before every create, like ul_set_view_flags. */
+EXPORT void ul_set_view_font_family(const char* family) {
+    snprintf(g_view_font_family, FONT_FAMILY_MAX, "%s", family ? family : "");
+}
+
+/* Sets the device scale, in permille, of views created after this call
+ * (1000 = 1x). Go calls it before every create, like ul_set_view_flags. */
+EXPORT void ul_set_view_device_scale(int permille) {
+    g_view_scale = permille > 0 ? permille : 1000;
+}
+
+/* Writes the rendering backend name to buf. Returns 1 for GPU-accelerated
+ * views, 0 for the CPU renderer, or CREATE_ERR_NOT_INIT if the renderer
+ * has not been created. */
+EXPORT int ul_renderer_info(char* buf, int buf_size) {
+    if (!g_renderer) return CREATE_ERR_NOT_INIT;
+    if (buf && buf_size > 0) snprintf(buf, buf_size, "%s", VIEW_ACCELERATED ? "gpu" : "cpu");
+    return VIEW_ACCELERATED ? 1 : 0;
 }
 
 /* Devuelve 1 si la view esta lista (carga async completada), 0 si no. */
@@ -1881,6 +2518,13 @@ EXPORT int ul_view_is_ready(int view_id) {
     return g_views[view_id].load_phase == 0 ? 1 : 0;
 }
 
+/* Pause or resume a view. Paused views skip input and JS processing in ul_tick
+ * and stop animating; messages sent by the page are still queued for Go. */
+EXPORT void ul_view_set_paused(int view_id, int paused) {
+    if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return;
+    g_views[view_id].paused = paused != 0;
+}
+
 EXPORT void ul_tick(void) {
 #ifdef _WIN32
     if (!g_worker_thread) return;
@@ -1916,14 +2560,20 @@ EXPORT unsigned int ul_view_get_row_bytes(int view_id) {
     return pfn_SurfaceGetRowBytes(g_views[view_id].surface);
 }
 
-/* Copies BGRA->RGBA pixels to the destination buffer only if the surface changed.
- * Returns 1 if pixels were copied (dirty), 0 if no changes. */
-EXPORT int ul_view_copy_pixels_rgba(int view_id, unsigned char* dest, int dest_size) {
+/* Copies BGRA->RGBA pixels to the destination buffer only if the surface changed,
+ * or always with force. With partial, only the dirty rect is converted, in place
+ * in the full-frame dest (which must hold the previous frame). Returns 1 if
+ * pixels were copied, 0 otherwise. The surface rows are rowBytes apart, which
+ * may exceed w*4 (row padding); dest rows are always packed at w*4. */
+static int copy_pixels_rgba(int view_id, unsigned char* dest, int dest_size, bool force, bool partial) {
     if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used || !g_views[view_id].surface) return 0;
     ViewSlot* v = &g_views[view_id];
     /* Check if the surface has changes */
     ULIntRect dirty = pfn_SurfaceGetDirtyBounds(v->surface);
-    if (dirty.left >= dirty.right || dirty.top >= dirty.bottom) return 0;
+    if (dirty.left >= dirty.right || dirty.top >= dirty.bottom) {
+        if (!force) return 0;
+        dirty.left = 0; dirty.top = 0; dirty.right = v->width; dirty.bottom = v->height;
+    }
     unsigned char* src = (unsigned char*)pfn_SurfaceLockPixels(v->surface);
     if (!src) return 0;
     int w = v->width;
@@ -1934,16 +2584,29 @@ EXPORT int ul_view_copy_pixels_rgba(int view_id, unsigned char* dest, int dest_s
         pfn_SurfaceUnlockPixels(v->surface);
         return 0;
     }
+    if (rowBytes < (unsigned int)w * 4) {
+        /* A row narrower than the view would be read past its end */
+        blog("copy_pixels_rgba: view %d row bytes %u < width %d * 4", view_id, rowBytes, w);
+        pfn_SurfaceUnlockPixels(v->surface);
+        return 0;
+    }
     int needed = w * h * 4;
     if (dest_size < needed) {
         pfn_SurfaceUnlockPixels(v->surface);
         return 0;
     }
+    int x0 = 0, y0 = 0, x1 = w, y1 = h;
+    if (partial) {
+        if (dirty.left > x0) x0 = dirty.left;
+        if (dirty.top > y0) y0 = dirty.top;
+        if (dirty.right < x1) x1 = dirty.right;
+        if (dirty.bottom < y1) y1 = dirty.bottom;
+    }
     /* BGRA -> RGBA conversion in C (much faster than Go) */
-    int dstIdx = 0;
-    for (int y = 0; y < h; y++) {
+    for (int y = y0; y < y1; y++) {
         unsigned char* row = src + y * rowBytes;
-        for (int x = 0; x < w; x++) {
+        int dstIdx = (y * w + x0) * 4;
+        for (int x = x0; x < x1; x++) {
             int off = x * 4;
             dest[dstIdx+0] = row[off+2];
             dest[dstIdx+1] = row[off+1];
@@ -1954,9 +2617,91 @@ EXPORT int ul_view_copy_pixels_rgba(int view_id, unsigned char* dest, int dest_s
     }
     pfn_SurfaceUnlockPixels(v->surface);
     pfn_SurfaceClearDirtyBounds(v->surface);
+    v->last_dirty = dirty;
     return 1;
 }
 
+EXPORT int ul_view_copy_pixels_rgba(int view_id, unsigned char* dest, int dest_size) {
+    return copy_pixels_rgba(view_id, dest, dest_size, false, false);
+}
+
+/* Like ul_view_copy_pixels_rgba, but copies even when the surface has no dirty
+ * bounds (e.g. the first frame after a hidden view is shown again). */
+EXPORT int ul_view_copy_pixels_rgba_force(int view_id, unsigned char* dest, int dest_size) {
+    return copy_pixels_rgba(view_id, dest, dest_size, true, false);
+}
+
+/* Like ul_view_copy_pixels_rgba, but converts only the dirty rect (reported by
+ * ul_view_get_last_dirty). dest must still hold the previously copied frame. */
+EXPORT int ul_view_copy_pixels_rgba_dirty(int view_id, unsigned char* dest, int dest_size) {
+    return copy_pixels_rgba(view_id, dest, dest_size, false, true);
+}
+
+/* Writes the dirty bounds of the last pixel copy as left, top, right, bottom. */
+EXPORT void ul_view_get_last_dirty(int view_id, int* out) {
+    if (!out) return;
+    out[0] = out[1] = out[2] = out[3] = 0;
+    if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return;
+    ULIntRect r = g_views[view_id].last_dirty;
+    out[0] = r.left; out[1] = r.top; out[2] = r.right; out[3] = r.bottom;
+}
+
+/* Returns 1 if the next ul_tick has work for the view: the last tick painted
+ * it, input or JS is queued, or it is loading. 0 for idle and paused views.
+ * JS timers are not tracked: they only fire on ticks. */
+EXPORT int ul_view_needs_paint(int view_id) {
+    if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return 0;
+    ViewSlot* v = &g_views[view_id];
+    if (v->paused) return v->paused_applied ? 0 : 1;
+    if (v->load_phase != 0 || v->painted) return 1;
+    VIEW_LOCK(v);
+    int pending = v->mouse_count + v->scroll_count + v->key_count + v->js_count + v->binary_count > 0 ||
+                  LOAD_OPEN(v);
+    VIEW_UNLOCK(v);
+    return pending;
+}
+
+/* Fills out[4] with the view's load state: progress in permille, then the
+ * number of main-frame loads started, finished and failed. Without the SDK
+ * load callbacks, progress is 1000 once the view is ready. */
+EXPORT void ul_view_get_load_state(int view_id, int* out) {
+    if (!out) return;
+    out[0] = out[1] = out[2] = out[3] = 0;
+    if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return;
+    ViewSlot* v = &g_views[view_id];
+    VIEW_LOCK(v);
+    out[0] = v->load_progress;
+    out[1] = v->load_starts;
+    out[2] = v->load_finishes;
+    out[3] = v->load_fails;
+    VIEW_UNLOCK(v);
+    if (!pfn_ViewSetBeginLoadingCallback) out[0] = v->load_phase == 0 ? 1000 : 0;
+}
+
+/* Returns 1 once the main frame's DOM has been parsed (DOMReady or
+ * FinishLoading fired), 0 before, and -1 if this SDK offers neither callback,
+ * in which case the caller has to fall back to a heuristic. */
+EXPORT int ul_view_is_dom_ready(int view_id) {
+    if (!pfn_ViewSetDOMReadyCallback && !pfn_ViewSetFinishLoadingCallback) return -1;
+    if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return 0;
+    return g_views[view_id].dom_ready ? 1 : 0;
+}
+
+/* Copies the last main-frame load failure's URL and description into the
+ * buffers (NUL-terminated, truncated) and returns its error code (0 if none). */
+EXPORT int ul_view_get_load_error(int view_id, char* url_buf, int url_size, char* desc_buf, int desc_size) {
+    if (url_buf && url_size > 0) url_buf[0] = '\0';
+    if (desc_buf && desc_size > 0) desc_buf[0] = '\0';
+    if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return 0;
+    ViewSlot* v = &g_views[view_id];
+    VIEW_LOCK(v);
+    int code = v->load_error_code;
+    if (url_buf && url_size > 0) snprintf(url_buf, (size_t)url_size, "%s", v->load_error_url);
+    if (desc_buf && desc_size > 0) snprintf(desc_buf, (size_t)desc_size, "%s", v->load_error_desc);
+    VIEW_UNLOCK(v);
+    return code;
+}
+
 /* Returns the actual surface width (may differ from requested on HiDPI) */
 EXPORT int ul_view_get_surface_width(int view_id) {
     if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return 0;
@@ -2088,6 +2833,83 @@ EXPORT void ul_view_eval_js(int view_id, const char* js) {
     VIEW_UNLOCK(v);
 }
 
+/* Evaluates js in the view on the worker thread and waits for the result.
+ * Returns 0 on success, 1 if the script threw, negative on error. The result
+ * (or exception message) is read with ul_eval_result_len / ul_eval_result_copy;
+ * callers must serialize eval + read. */
+EXPORT int ul_view_eval_sync(int view_id, const char* js) {
+#ifdef _WIN32
+    if (!g_worker_thread) return -1;
+#else
+    if (!g_worker_started) return -1;
+#endif
+    if (view_id < 0 || view_id >= MAX_VIEWS || !js) return -1;
+    return send_cmd(CMD_EVAL_SYNC, js, view_id, 0);
+}
+
+/* History navigation and Stop on the worker: op is a NAV_* value. See worker_do_navigate. */
+EXPORT int ul_view_navigate(int view_id, int op) {
+#ifdef _WIN32
+    if (!g_worker_thread) return -1;
+#else
+    if (!g_worker_started) return -1;
+#endif
+    if (view_id < 0 || view_id >= MAX_VIEWS) return -1;
+    return send_cmd(CMD_NAVIGATE, NULL, view_id, op);
+}
+
+/* Changes a view's device scale (window.devicePixelRatio), in permille. */
+EXPORT int ul_view_set_device_scale(int view_id, int permille) {
+#ifdef _WIN32
+    if (!g_worker_thread) return -1;
+#else
+    if (!g_worker_started) return -1;
+#endif
+    if (view_id < 0 || view_id >= MAX_VIEWS) return -1;
+    return send_cmd(CMD_SET_DEVICE_SCALE, NULL, view_id, permille);
+}
+
+/* Resizes a view. Width and height go to the worker packed in one int, so
+ * each is limited to 1..32767. Go drops its frame buffers and texture. */
+EXPORT int ul_view_resize(int view_id, int width, int height) {
+#ifdef _WIN32
+    if (!g_worker_thread) return -1;
+#else
+    if (!g_worker_started) return -1;
+#endif
+    if (view_id < 0 || view_id >= MAX_VIEWS) return -1;
+    if (width <= 0 || height <= 0 || width > 0x7FFF || height > 0x7FFF) return -1;
+    return send_cmd(CMD_RESIZE, NULL, view_id, (width << 16) | height);
+}
+
+/* Length in bytes of the last EvalSync result. */
+EXPORT int ul_eval_result_len(void) {
+    return g_eval_result ? g_eval_result_len : 0;
+}
+
+/* Copies the last EvalSync result into buf (NUL-terminated, truncated to
+ * buf_size - 1). Returns the number of bytes copied. */
+EXPORT int ul_eval_result_copy(char* buf, int buf_size) {
+    if (!buf || buf_size <= 0) return 0;
+    int n = g_eval_result ? g_eval_result_len : 0;
+    if (n > buf_size - 1) n = buf_size - 1;
+    if (n > 0) memcpy(buf, g_eval_result, n);
+    buf[n] = '\0';
+    return n;
+}
+
+/* Returns the length in bytes of the next queued message (without the NUL
+ * terminator), or -1 if the queue is empty. Lets Go size its buffer before
+ * calling ul_view_get_message, which truncates to buf_size - 1. */
+EXPORT int ul_view_get_message_len(int view_id) {
+    if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return -1;
+    ViewSlot* v = &g_views[view_id];
+    VIEW_LOCK(v);
+    int len = v->msg_count > 0 ? v->msg_lens[v->msg_tail] : -1;
+    VIEW_UNLOCK(v);
+    return len;
+}
+
 EXPORT int ul_view_get_message(int view_id, char* buf, int buf_size) {
     if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used || !buf || buf_size <= 0) return 0;
     ViewSlot* v = &g_views[view_id];
@@ -2105,14 +2927,69 @@ EXPORT int ul_view_get_message(int view_id, char* buf, int buf_size) {
     return cl;
 }
 
-EXPORT int ul_view_get_console_message(int view_id, char* buf, int buf_size) {
+/* Bytes held by a circular string queue (msg_queue / console_msgs). */
+static size_t circ_queue_bytes(int* lens, int capacity, int tail, int count) {
+    size_t n = (size_t)capacity * (sizeof(char*) + sizeof(int));
+    for (int i = 0; i < count; i++) n += (size_t)lens[(tail + i) % capacity] + 1;
+    return n;
+}
+
+/* Approximate native memory held for a view: the surface bitmap plus the
+ * bridge's queued messages, scripts and binary payloads. Ultralight's C API
+ * has no per-view heap stats, so DOM, JS heap and cache memory are not counted. */
+static long long view_memory_usage(ViewSlot* v) {
+    long long n = 0;
+    if (v->surface) {
+        int h = v->surface_height > 0 ? v->surface_height : v->height;
+        n += (long long)pfn_SurfaceGetRowBytes(v->surface) * h;
+    }
+    VIEW_LOCK(v);
+    n += (long long)circ_queue_bytes(v->msg_lens, v->msg_capacity, v->msg_tail, v->msg_count);
+    n += (long long)circ_queue_bytes(v->console_lens, v->console_capacity, v->console_tail, v->console_count);
+    n += (long long)v->js_capacity * sizeof(char*);
+    for (int i = 0; i < v->js_count; i++)
+        if (v->js_queue[i]) n += (long long)strlen(v->js_queue[i]) + 1;
+    n += (long long)v->binary_capacity * sizeof(BinaryQueueEntry);
+    for (int i = 0; i < v->binary_count; i++) {
+        BinaryQueueEntry* be = &v->binary_queue[i];
+        if (be->props_json) n += (long long)strlen(be->props_json) + 1;
+        if (be->bin_key) n += (long long)strlen(be->bin_key) + 1;
+        n += (long long)be->bin_len;
+    }
+    if (v->pending_load_str) n += (long long)strlen(v->pending_load_str) + 1;
+    VIEW_UNLOCK(v);
+    return n;
+}
+
+/* Approximate native memory of one view (see view_memory_usage), or -1 if the
+ * view doesn't exist. */
+EXPORT long long ul_view_memory_usage(int view_id) {
+    if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used) return -1;
+    return view_memory_usage(&g_views[view_id]);
+}
+
+/* Approximate native memory of all views plus the files registered in the VFS. */
+EXPORT long long ul_memory_usage_total(void) {
+    long long n = 0;
+    for (int i = 0; i < MAX_VIEWS; i++)
+        if (g_views[i].used) n += view_memory_usage(&g_views[i]);
+    for (int i = 0; i < g_vfs_count; i++)
+        n += (long long)g_vfs_files[i].size;
+    return n;
+}
+
+/* Pops the oldest console message into buf (truncated, NUL-terminated) and
+ * its ULMessageLevel into *level. Returns the copied length, 0 if none. */
+static int pop_console_message(int view_id, char* buf, int buf_size, int* level) {
     if (view_id < 0 || view_id >= MAX_VIEWS || !g_views[view_id].used || !buf || buf_size <= 0) return 0;
     ViewSlot* v = &g_views[view_id];
     VIEW_LOCK(v);
     if (v->console_count <= 0) { VIEW_UNLOCK(v); return 0; }
-    int len = v->console_lens[v->console_tail];
+    const char* entry = v->console_msgs[v->console_tail];
+    int len = v->console_lens[v->console_tail] - 1;
     int cl = len < (buf_size - 1) ? len : (buf_size - 1);
-    memcpy(buf, v->console_msgs[v->console_tail], cl);
+    if (level) *level = (unsigned char)entry[0];
+    memcpy(buf, entry + 1, cl);
     buf[cl] = '\0';
     free(v->console_msgs[v->console_tail]);
     v->console_msgs[v->console_tail] = NULL;
@@ -2122,8 +2999,30 @@ EXPORT int ul_view_get_console_message(int view_id, char* buf, int buf_size) {
     return cl;
 }
 
+EXPORT int ul_view_get_console_message(int view_id, char* buf, int buf_size) {
+    return pop_console_message(view_id, buf, buf_size, NULL);
+}
+
+/* Like ul_view_get_console_message, also writing the message's ULMessageLevel
+ * (1 log, 2 warning, 3 error, 4 debug, 5 info) to *level. */
+EXPORT int ul_view_get_console_message_level(int view_id, char* buf, int buf_size, int* level) {
+    return pop_console_message(view_id, buf, buf_size, level);
+}
+
 /* ── VFS exports for Go ──────────────────────────────────────────────── */
-EXPORT int ul_vfs_register(const char* path, const void* data, long long size) {
+/* Copies mime into the entry; NULL or "" clears it (infer from extension). */
+static void vfs_set_mime(VfsEntry* e, const char* mime) {
+    if (mime && mime[0]) {
+        strncpy(e->mime, mime, VFS_MIME_MAX - 1);
+        e->mime[VFS_MIME_MAX - 1] = '\0';
+    } else {
+        e->mime[0] = '\0';
+    }
+}
+
+/* Registers a file with an explicit content type. mime may be NULL or "" to
+ * fall back to vfs_mime_for_ext when Ultralight asks for the type. */
+EXPORT int ul_vfs_register_typed(const char* path, const void* data, long long size, const char* mime) {
     if (!path || !data || size < 0) return -1;
     char norm[VFS_PATH_MAX];
     vfs_normalize_path(path, norm, VFS_PATH_MAX);
@@ -2135,7 +3034,8 @@ EXPORT int ul_vfs_register(const char* path, const void* data, long long size) {
         if (!g_vfs_files[idx].data) return -2;
         memcpy(g_vfs_files[idx].data, data, (size_t)size);
         g_vfs_files[idx].size = (size_t)size;
-        blog("vfs_register: overwrite '%s' size=%lld", norm, size);
+        vfs_set_mime(&g_vfs_files[idx], mime);
+        blog("vfs_register: overwrite '%s' size=%lld mime='%s'", norm, size, g_vfs_files[idx].mime);
         return 0;
     }
     if (g_vfs_count >= VFS_MAX_FILES) { blog("vfs_register: FULL"); return -3; }
@@ -2146,8 +3046,30 @@ EXPORT int ul_vfs_register(const char* path, const void* data, long long size) {
     if (!e->data) return -2;
     memcpy(e->data, data, (size_t)size);
     e->size = (size_t)size;
+    vfs_set_mime(e, mime);
     g_vfs_count++;
-    blog("vfs_register: '%s' size=%lld count=%d", norm, size, g_vfs_count);
+    blog("vfs_register: '%s' size=%lld mime='%s' count=%d", norm, size, e->mime, g_vfs_count);
+    return 0;
+}
+
+EXPORT int ul_vfs_register(const char* path, const void* data, long long size) {
+    return ul_vfs_register_typed(path, data, size, NULL);
+}
+
+/* Removes one file (path normalized like ul_vfs_register). The last entry
+ * takes its slot, so the table stays packed. Returns 0, or -4 if the path was
+ * not registered. */
+EXPORT int ul_vfs_unregister(const char* path) {
+    if (!path) return -1;
+    char norm[VFS_PATH_MAX];
+    vfs_normalize_path(path, norm, VFS_PATH_MAX);
+    int idx = vfs_find(norm);
+    if (idx < 0) { blog("vfs_unregister: not found '%s'", norm); return -4; }
+    free(g_vfs_files[idx].data);
+    g_vfs_count--;
+    if (idx != g_vfs_count) g_vfs_files[idx] = g_vfs_files[g_vfs_count];
+    memset(&g_vfs_files[g_vfs_count], 0, sizeof(VfsEntry));
+    blog("vfs_unregister: '%s' count=%d", norm, g_vfs_count);
     return 0;
 }
 
@@ -2157,6 +3079,7 @@ EXPORT void ul_vfs_clear(void) {
         g_vfs_files[i].data = NULL;
         g_vfs_files[i].size = 0;
         g_vfs_files[i].path[0] = '\0';
+        g_vfs_files[i].mime[0] = '\0';
     }
     g_vfs_count = 0;
     blog("vfs_clear: done");
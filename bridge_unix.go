@@ -13,15 +13,14 @@ import (
 	"github.com/ebitengine/purego"
 )
 
-func doInitBridge(baseDir string) error {
-	libPath := filepath.Join(baseDir, bridgeLibName())
+func doInitBridge(libPath string) error {
 	absPath, err := filepath.Abs(libPath)
 	if err != nil {
 		absPath = libPath
 	}
 	handle, err := purego.Dlopen(absPath, purego.RTLD_NOW|purego.RTLD_GLOBAL)
 	if err != nil {
-		return fmt.Errorf("failed to load %s from %s: %w", bridgeLibName(), absPath, err)
+		return fmt.Errorf("failed to load %s from %s: %w", filepath.Base(absPath), absPath, err)
 	}
 	return resolveAllSymbols(handle)
 }
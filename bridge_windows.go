@@ -11,15 +11,14 @@ import (
 	"syscall"
 )
 
-func doInitBridge(baseDir string) error {
-	dllPath := filepath.Join(baseDir, "ul_bridge.dll")
+func doInitBridge(dllPath string) error {
 	absPath, err := filepath.Abs(dllPath)
 	if err != nil {
 		absPath = dllPath
 	}
 	lib, err := syscall.LoadLibrary(absPath)
 	if err != nil {
-		return fmt.Errorf("failed to load ul_bridge.dll from %s: %w", absPath, err)
+		return fmt.Errorf("failed to load %s from %s: %w", filepath.Base(absPath), absPath, err)
 	}
 	return resolveAllSymbols(uintptr(lib))
 }
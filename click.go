@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"strconv"
+	"time"
+)
+
+// Multi-click detection. Ultralight's mouse events carry no click count, so
+// double/triple clicks are detected here and their default actions (dblclick
+// event, word and line selection) are performed by a script after the mouse up.
+const (
+	// multiClickWindow is the maximum time between presses of one multi-click,
+	// the Windows default double-click time.
+	multiClickWindow = 500 * time.Millisecond
+	// multiClickSlop is how far (in view pixels, each axis) the cursor may move
+	// between presses of one multi-click.
+	multiClickSlop = 4
+)
+
+// clickCounter counts consecutive left presses at about the same position.
+type clickCounter struct {
+	last  time.Time
+	x, y  int
+	count int // 1 = single, 2 = double, 3 = triple
+}
+
+// press records a press at view coordinates x, y and returns the click count.
+// A fourth quick press starts over at 1, like a browser cycles selections.
+func (c *clickCounter) press(now time.Time, x, y int) int {
+	if c.count > 0 && c.count < 3 && now.Sub(c.last) <= multiClickWindow &&
+		abs(x-c.x) <= multiClickSlop && abs(y-c.y) <= multiClickSlop {
+		c.count++
+	} else {
+		c.count = 1
+	}
+	c.last, c.x, c.y = now, x, y
+	return c.count
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// multiClickScript returns the script run after the mouse up of a double
+// (n=2) or triple (n=3) click at view coordinates x, y: a double click fires
+// dblclick and selects the word under the caret, a triple click the line
+// (textarea) or paragraph; an <input> selects its whole value on triple click.
+func multiClickScript(n, x, y int) string {
+	return `(function(n,x,y){
+if(n===2){var el=document.elementFromPoint(x,y);if(el)el.dispatchEvent(new MouseEvent('dblclick',{bubbles:true,cancelable:true,view:window,detail:2,clientX:x,clientY:y}))}
+var e=document.activeElement,t=e&&e.tagName;
+if((t==='INPUT'||t==='TEXTAREA')&&typeof e.selectionStart==='number'){
+var v=e.value,p=e.selectionStart,a,b;
+if(n===2){var w=/[0-9A-Za-z_À-￿]/;
+function k(ch){return w.test(ch)?1:/\s/.test(ch)?2:3}
+var c=p;if(c>=v.length||(!w.test(v.charAt(c))&&c>0&&w.test(v.charAt(c-1))))c--;if(c<0)return;
+var cl=k(v.charAt(c));a=c;b=c+1;
+while(a>0&&k(v.charAt(a-1))===cl)a--;while(b<v.length&&k(v.charAt(b))===cl)b++}
+else if(t==='TEXTAREA'){a=v.lastIndexOf('\n',p-1)+1;b=v.indexOf('\n',p);if(b<0)b=v.length}
+else{a=0;b=v.length}
+try{e.setSelectionRange(a,b)}catch(_){}return}
+var s=window.getSelection();if(!s||!s.modify)return;
+if(document.caretRangeFromPoint){var r=document.caretRangeFromPoint(x,y);if(r){s.removeAllRanges();s.addRange(r)}}
+if(!s.rangeCount)return;
+var u=n===2?'word':'paragraphboundary';
+s.collapseToStart();s.modify('move','forward',u);s.modify('move','backward',u);s.modify('extend','forward',u);
+})(` + strconv.Itoa(n) + `,` + strconv.Itoa(x) + `,` + strconv.Itoa(y) + `)`
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+// InjectCSS adds a stylesheet to the page and returns a handle for RemoveCSS,
+// e.g. to switch themes at runtime. The CSS is inserted as a
+// <style data-ul-id> element, so it needs no escaping. Stylesheets injected
+// before the DOM is ready are inserted once it is.
+func (ui *UltralightUI) InjectCSS(css string) (handleID int, err error) {
+	if ui.closed.Load() {
+		return 0, ErrClosed
+	}
+	ui.cssSeq++
+	id := ui.cssSeq
+	if !ui.domReady {
+		if ui.pendingCSS == nil {
+			ui.pendingCSS = make(map[int]string)
+		}
+		ui.pendingCSS[id] = css
+		return id, nil
+	}
+	ui.Eval(injectCSSScript(id, css))
+	return id, nil
+}
+
+// RemoveCSS removes a stylesheet added with InjectCSS. Unknown handles are ignored.
+func (ui *UltralightUI) RemoveCSS(handleID int) {
+	if _, ok := ui.pendingCSS[handleID]; ok {
+		delete(ui.pendingCSS, handleID)
+		return
+	}
+	if ui.domReady {
+		ui.Eval(`(function(){var s=document.querySelector('style[data-ul-id="` + strconv.Itoa(handleID) + `"]');if(s)s.parentNode.removeChild(s)})()`)
+	}
+}
+
+// applyPendingCSS inserts the stylesheets injected before the DOM was ready,
+// in injection order so later ones still win the cascade.
+func (ui *UltralightUI) applyPendingCSS() {
+	ids := make([]int, 0, len(ui.pendingCSS))
+	for id := range ui.pendingCSS {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		ui.Eval(injectCSSScript(id, ui.pendingCSS[id]))
+	}
+	ui.pendingCSS = nil
+}
+
+func injectCSSScript(id int, css string) string {
+	text, _ := json.Marshal(css)
+	return `(function(){var s=document.createElement('style');s.setAttribute('data-ul-id','` + strconv.Itoa(id) + `');s.textContent=` + string(text) + `;(document.head||document.documentElement).appendChild(s)})()`
+}
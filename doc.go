@@ -57,6 +57,13 @@
 // Mouse and scroll input are forwarded when the cursor is inside the view's bounds.
 // Keyboard input goes to whichever view has focus (via SetFocus or clicking).
 //
+// Threading: Ultralight itself only runs on the bridge's worker thread. Every
+// bridge call is handed to that thread and calls from different goroutines are
+// serialized, so the package may be used from any goroutine (Ebitengine's
+// Update loop is the usual one). An UltralightUI is not safe for concurrent use,
+// though: call its methods from one goroutine at a time. Overlapping Update
+// calls on the same view are detected and return [ErrConcurrentUpdate].
+//
 // Requirements: the bridge shared library (ul_bridge.dll on Windows,
 // libul_bridge.so on Linux, libul_bridge.dylib on macOS) and the Ultralight 1.4
 // SDK libraries must be present next to the executable or in the directory
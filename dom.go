@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrElementNotFound is returned by GetAttribute when no element matches the
+// selector.
+var ErrElementNotFound = errors.New("ultralightui: no element matches the selector")
+
+// ErrAttributeNotFound is returned by GetAttribute when the element doesn't
+// have the attribute, so a missing attribute can be told from an empty one.
+var ErrAttributeNotFound = errors.New("ultralightui: element has no such attribute")
+
+// QuerySelector reports whether an element matches selector, e.g. to wait for
+// a button to appear. Pseudo-classes give live state: "#opt:checked" matches
+// only while the checkbox is checked. An invalid selector returns the
+// page's SyntaxError. The view must be ready (see EvalSync).
+func (ui *UltralightUI) QuerySelector(selector string) (exists bool, err error) {
+	sel, _ := json.Marshal(selector)
+	res, err := ui.EvalSync(`document.querySelector(` + string(sel) + `)!==null?'1':'0'`)
+	if err != nil {
+		return false, err
+	}
+	return res == "1", nil
+}
+
+// GetAttribute returns the attribute attr of the first element matching
+// selector, or ErrElementNotFound / ErrAttributeNotFound. It reads the HTML
+// attribute, not the live property: a checkbox's "checked" attribute doesn't
+// follow clicks (use QuerySelector with :checked instead), nor does an
+// input's "value" follow typing. The view must be ready (see EvalSync).
+func (ui *UltralightUI) GetAttribute(selector, attr string) (string, error) {
+	sel, _ := json.Marshal(selector)
+	name, _ := json.Marshal(attr)
+	res, err := ui.EvalSync(`(function(){var e=document.querySelector(` + string(sel) + `);
+return JSON.stringify(e?[e.getAttribute(` + string(name) + `)]:null)})()`)
+	if err != nil {
+		return "", err
+	}
+	var v *[1]*string
+	if err := json.Unmarshal([]byte(res), &v); err != nil {
+		return "", fmt.Errorf("GetAttribute: unexpected result %q: %w", res, err)
+	}
+	switch {
+	case v == nil:
+		return "", fmt.Errorf("%w: %s", ErrElementNotFound, selector)
+	case v[0] == nil:
+		return "", fmt.Errorf("%w: %s %s", ErrAttributeNotFound, selector, attr)
+	}
+	return *v[0], nil
+}
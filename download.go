@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// downloadChunkSize is the number of base64 characters per __download message.
+// It must be a multiple of 4 so every chunk decodes on its own, and stays well
+// under pollMessage's 64 KB stack buffer so chunks don't need an allocation.
+const downloadChunkSize = 24576
+
+// pendingDownload accumulates the chunks of a download sent by the page.
+type pendingDownload struct {
+	name   string
+	data   []byte
+	failed bool
+}
+
+// downloadMsg is one chunk of a download, as sent by the helper script.
+type downloadMsg struct {
+	Action string `json:"action"`
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Data   string `json:"data"`
+	Last   bool   `json:"last"`
+	Error  string `json:"error"`
+}
+
+// injectDownloadHelper installs the script that captures page downloads.
+// Ultralight has no download manager, so clicks on <a download> (including
+// programmatic a.click() on detached anchors) are intercepted in JS, the
+// target is read (blob:, data: or any URL the page can XHR) and the bytes are
+// streamed to Go as base64 chunks via go.send. Blobs are tracked by wrapping
+// URL.createObjectURL, so the common "click then revokeObjectURL" pattern works.
+func (ui *UltralightUI) injectDownloadHelper() {
+	ui.Eval(`(function(){
+if(window.__ulDlInit)return;window.__ulDlInit=1;
+var blobs={},next=0,CH=` + strconv.Itoa(downloadChunkSize) + `;
+function send(m){if(window.go&&window.go.send)window.go.send(m)}
+if(window.URL&&URL.createObjectURL){
+var oc=URL.createObjectURL,orv=URL.revokeObjectURL;
+URL.createObjectURL=function(o){var u=oc.apply(URL,arguments);if(typeof Blob!=='undefined'&&o instanceof Blob)blobs[u]=o;return u};
+if(orv)URL.revokeObjectURL=function(u){delete blobs[u];return orv.apply(URL,arguments)};
+}
+function b64(buf){var b=new Uint8Array(buf),s='';for(var i=0;i<b.length;i+=8192)s+=String.fromCharCode.apply(null,b.subarray(i,i+8192));return btoa(s)}
+function emit(id,name,data){
+if(!data.length){send({action:'__download',id:id,name:name,data:'',last:true});return}
+for(var i=0;i<data.length;i+=CH)send({action:'__download',id:id,name:name,data:data.slice(i,i+CH),last:i+CH>=data.length});
+}
+function fail(id,name,err){send({action:'__download',id:id,name:name,error:String(err||'failed'),last:true})}
+function fromDataURL(u){var i=u.indexOf(','),meta=u.slice(5,i),body=u.slice(i+1);
+if(/;base64$/i.test(meta))return body.replace(/\s/g,'');
+return btoa(unescape(encodeURIComponent(decodeURIComponent(body))))}
+function capture(a){
+var href=a.href||a.getAttribute('href')||'';if(!href)return false;
+var name=a.getAttribute('download')||decodeURIComponent(href.split(/[?#]/)[0].split('/').pop()||'')||'download';
+var id=++next,blob=blobs[href];
+if(blob){var r=new FileReader();
+r.onload=function(){var s=String(r.result);emit(id,name,s.slice(s.indexOf(',')+1))};
+r.onerror=function(){fail(id,name,r.error)};
+r.readAsDataURL(blob);return true}
+if(href.slice(0,5)==='data:'){try{emit(id,name,fromDataURL(href))}catch(e){fail(id,name,e)}return true}
+var x=new XMLHttpRequest();
+try{x.open('GET',href,true);x.responseType='arraybuffer';
+x.onload=function(){if(x.status&&(x.status<200||x.status>=300)){fail(id,name,'HTTP '+x.status);return}emit(id,name,b64(x.response))};
+x.onerror=function(){fail(id,name,'request failed')};
+x.send()}catch(e){fail(id,name,e)}
+return true}
+function isDl(a){return a&&a.tagName==='A'&&a.hasAttribute('download')}
+document.addEventListener('click',function(ev){
+var a=ev.target;while(a&&a.tagName!=='A')a=a.parentElement;
+if(isDl(a)&&capture(a))ev.preventDefault();
+},true);
+var ac=HTMLAnchorElement.prototype.click;
+HTMLAnchorElement.prototype.click=function(){
+if(isDl(this)&&!document.documentElement.contains(this)){capture(this);return}
+return ac.apply(this,arguments)};
+})();`)
+}
+
+// handleDownloadMsg intercepts __download chunks sent by the download helper
+// and calls OnDownload once a download is complete. Returns true if the
+// message was consumed (caller should skip OnMessage).
+func (ui *UltralightUI) handleDownloadMsg(msg string) bool {
+	if !strings.HasPrefix(msg, "{\"action\":\"__download\"") {
+		return false
+	}
+	var d downloadMsg
+	if json.Unmarshal([]byte(msg), &d) != nil || d.Action != "__download" {
+		return false
+	}
+	if ui.downloads == nil {
+		ui.downloads = make(map[int]*pendingDownload)
+	}
+	p := ui.downloads[d.ID]
+	if p == nil {
+		p = &pendingDownload{name: sanitizeDownloadName(d.Name)}
+		ui.downloads[d.ID] = p
+	}
+	if d.Error != "" {
+		p.failed = true
+	} else if !p.failed && d.Data != "" {
+		chunk, err := base64.StdEncoding.DecodeString(d.Data)
+		if err != nil {
+			p.failed = true
+		}
+		p.data = append(p.data, chunk...)
+	}
+	if d.Last {
+		delete(ui.downloads, d.ID)
+		// Failed downloads are dropped rather than delivered truncated.
+		if !p.failed && ui.OnDownload != nil {
+			data := p.data
+			if data == nil {
+				data = []byte{}
+			}
+			ui.OnDownload(p.name, data)
+		}
+	}
+	return true
+}
+
+// sanitizeDownloadName reduces a page-supplied file name to its last path
+// element, so it can't be used to escape a host-chosen directory.
+func sanitizeDownloadName(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." {
+		return "download"
+	}
+	return name
+}
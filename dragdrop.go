@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"io/fs"
+	"strconv"
+)
+
+// droppedFile is the metadata of a dropped file exposed to the page.
+type droppedFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Type string `json:"type"`
+}
+
+// handleFileDrop delivers files dropped at offset-adjusted screen position
+// (mx, my): OnFileDrop gets the same coordinates as mouse events, and the page
+// receives dragenter/dragover/drop events at the element under the cursor.
+func (ui *UltralightUI) handleFileDrop(files fs.FS, mx, my int) {
+	infos := droppedFileInfos(files)
+	if len(infos) == 0 {
+		return
+	}
+	if ui.OnFileDrop != nil {
+		names := make([]string, len(infos))
+		for i, f := range infos {
+			names[i] = f.Name
+		}
+		lx, ly := ui.toLocal(mx, my)
+		ui.OnFileDrop(files, names, lx, ly)
+	}
+	cx, cy := ui.viewCoords(mx, my)
+	ui.Eval(buildDropScript(infos, cx, cy))
+}
+
+// droppedFileInfos lists the regular files at the root of a dropped FS.
+func droppedFileInfos(files fs.FS) []droppedFile {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil
+	}
+	var infos []droppedFile
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		infos = append(infos, droppedFile{Name: e.Name(), Size: info.Size(), Type: detectMimeType(e.Name())})
+	}
+	return infos
+}
+
+// buildDropScript builds the JS that fires dragenter, dragover and drop at the
+// element under CSS pixel (x, y). event.dataTransfer.files carries name, size
+// and type only; the contents are available to Go through OnFileDrop.
+func buildDropScript(infos []droppedFile, x, y int) string {
+	data, _ := json.Marshal(infos)
+	return `(function(files,x,y){
+var t=document.elementFromPoint(x,y)||document.body;if(!t)return;
+var dt={files:files,items:files.map(function(f){return{kind:'file',type:f.type,getAsFile:function(){return f}}}),
+types:['Files'],dropEffect:'copy',effectAllowed:'all',getData:function(){return''},setData:function(){}};
+['dragenter','dragover','drop'].forEach(function(n){
+var e=document.createEvent('Event');e.initEvent(n,true,true);
+e.dataTransfer=dt;e.clientX=e.pageX=x;e.clientY=e.pageY=y;
+t.dispatchEvent(e);
+});
+})(` + string(data) + `,` + strconv.Itoa(x) + `,` + strconv.Itoa(y) + `);`
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"image/color"
 	"log"
@@ -44,7 +45,10 @@ func findBaseDir() string {
 func newGame() (*Game, error) {
 	opts := &ultralightui.Options{BaseDir: findBaseDir()}
 
-	mainUI, err := ultralightui.NewFromFile(mainUIWidth, screenHeight, "ui/index.html", opts)
+	// The main view takes keyboard focus on creation
+	mainOpts := *opts
+	mainOpts.Focused = true
+	mainUI, err := ultralightui.NewFromFile(mainUIWidth, screenHeight, "ui/index.html", &mainOpts)
 	if err != nil {
 		return nil, fmt.Errorf("main UI: %w", err)
 	}
@@ -56,12 +60,11 @@ func newGame() (*Game, error) {
 	}
 
 	g := &Game{mainUI: mainUI, sidebar: sidebar}
-	mainUI.OnMessage = g.handleMainMessage
+	mainUI.OnMessage = g.mainRouter().Dispatch
 	sidebar.OnMessage = g.handleSidebarMessage
 
 	mainUI.SetBounds(0, 0, mainUIWidth, screenHeight)
 	sidebar.SetBounds(mainUIWidth, 0, sidebarWidth, screenHeight)
-	mainUI.SetFocus()
 
 	return g, nil
 }
@@ -117,16 +120,20 @@ func (g *Game) Layout(_, _ int) (int, int) {
 	return screenWidth, screenHeight
 }
 
-func (g *Game) handleMainMessage(msg string) {
-	log.Printf("[main UI] message: %s", msg)
-	switch msg {
-	case "greet":
+// mainRouter dispatches the main view's messages by their "type" field.
+func (g *Game) mainRouter() *ultralightui.MessageRouter {
+	r := ultralightui.NewRouter()
+	r.Handle("greet", func(json.RawMessage) {
 		g.mainUI.Eval("showMessage('Hello from Go!')")
-	case "count":
+	})
+	r.Handle("count", func(json.RawMessage) {
 		g.mainUI.Eval(fmt.Sprintf("showMessage('Counter is at %d')", g.counter/60))
-	default:
+	})
+	r.Fallback(func(msg string) {
+		log.Printf("[main UI] message: %s", msg)
 		g.mainUI.Eval(fmt.Sprintf("showMessage('Go received: %s')", msg))
-	}
+	})
+	return r
 }
 
 func (g *Game) handleSidebarMessage(msg string) {
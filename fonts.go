@@ -0,0 +1,138 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// fontDir is the VFS directory RegisterFont puts fonts in.
+const fontDir = "_fonts/"
+
+// vfsFont is a font registered with RegisterFont.
+type vfsFont struct {
+	family string
+	path   string // VFS path
+	format string // @font-face format()
+}
+
+// Fonts registered with RegisterFont, in registration order (guarded by
+// vfsMu), and a counter bumped on every registration so views know to refresh
+// their @font-face rules; views poll it every Update without taking vfsMu.
+var (
+	vfsFonts []vfsFont
+	fontGen  atomic.Int64
+)
+
+// RegisterFont registers a WOFF2, WOFF, TrueType or OpenType font in the VFS
+// (detected from its content) and declares it as font family name in every
+// view, so pages can use it without their own @font-face:
+//
+//	ultralightui.RegisterFont("Inter", interWOFF2)
+//	// CSS: body { font-family: "Inter", sans-serif; }
+//
+// The font is served from file:///_fonts/<name>.<ext> with its font/*
+// content type, for pages that declare @font-face themselves. name may hold
+// letters, digits, spaces, '-' and '_'. Registering a name again replaces its
+// font (and removes its previous file if the format changed); views pick up
+// new fonts on their next Update, and pages loaded later get them all. Fonts
+// removed with UnregisterFile or ClearFiles are left out of pages loaded
+// afterwards.
+func RegisterFont(name string, data []byte) error {
+	if !validFontName(name) {
+		return fmt.Errorf("ultralightui: RegisterFont: invalid family name %q", name)
+	}
+	ext, format, mimeType := fontFormat(data)
+	if ext == "" {
+		return errors.New("ultralightui: RegisterFont: " + name + ": not a WOFF2, WOFF, TrueType or OpenType font")
+	}
+	p := fontDir + strings.ReplaceAll(name, " ", "-") + ext
+	if _, err := registerVFS(p, data, mimeType); err != nil {
+		return err
+	}
+	var old string
+	vfsMu.Lock()
+	for i := range vfsFonts {
+		if vfsFonts[i].family == name {
+			old = vfsFonts[i].path
+			vfsFonts = append(vfsFonts[:i], vfsFonts[i+1:]...)
+			break
+		}
+	}
+	vfsFonts = append(vfsFonts, vfsFont{family: name, path: p, format: format})
+	fontGen.Add(1)
+	vfsMu.Unlock()
+	if old != "" && old != p && ulVfsUnregister != nil {
+		// Gone already (UnregisterFile, ClearFiles) is fine.
+		UnregisterFile(old)
+	}
+	return nil
+}
+
+func validFontName(name string) bool {
+	if strings.TrimSpace(name) == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == ' ' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// fontFormat identifies a font by its signature, returning its file
+// extension, @font-face format and content type ("" if unknown).
+func fontFormat(data []byte) (ext, format, mimeType string) {
+	switch {
+	case bytes.HasPrefix(data, []byte("wOF2")):
+		return ".woff2", "woff2", "font/woff2"
+	case bytes.HasPrefix(data, []byte("wOFF")):
+		return ".woff", "woff", "font/woff"
+	case bytes.HasPrefix(data, []byte("OTTO")):
+		return ".otf", "opentype", "font/otf"
+	case bytes.HasPrefix(data, []byte{0, 1, 0, 0}), bytes.HasPrefix(data, []byte("true")):
+		return ".ttf", "truetype", "font/ttf"
+	}
+	return "", "", ""
+}
+
+// fontFaceCSS returns the @font-face rules for the registered fonts still in
+// the VFS and the generation they reflect, or ok false if fonts haven't
+// changed since generation since.
+func fontFaceCSS(since int64) (css string, gen int64, ok bool) {
+	if fontGen.Load() == since {
+		return "", since, false
+	}
+	vfsMu.Lock()
+	defer vfsMu.Unlock()
+	gen = fontGen.Load()
+	var b strings.Builder
+	for _, f := range vfsFonts {
+		if _, ok := vfsFiles[vfsPath(f.path)]; !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "@font-face{font-family:\"%s\";src:url(\"file:///%s\") format(\"%s\");}\n", f.family, f.path, f.format)
+	}
+	return b.String(), gen, true
+}
+
+// applyFonts (re)writes the page's <style data-ul-fonts> with the registered
+// fonts, if any were registered since it was last written.
+func (ui *UltralightUI) applyFonts() {
+	css, gen, ok := fontFaceCSS(ui.fontGen)
+	if !ok {
+		return
+	}
+	ui.fontGen = gen
+	text, _ := json.Marshal(css)
+	ui.Eval(`(function(){var s=document.querySelector('style[data-ul-fonts]');` +
+		`if(!s){s=document.createElement('style');s.setAttribute('data-ul-fonts','');(document.head||document.documentElement).appendChild(s)}` +
+		`s.textContent=` + string(text) + `})()`)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"time"
+)
+
+// RenderOnceTimeout bounds how long RenderOnce waits for the page to load and paint.
+var RenderOnceTimeout = 10 * time.Second
+
+// renderOnceInterval is the pause between renderer ticks while RenderOnce waits,
+// leaving the bridge worker time for resource loading and JS timers.
+const renderOnceInterval = 4 * time.Millisecond
+
+// RenderOnce renders html into a width x height image and tears the view down.
+// It never creates an Ebiten image, so it works without a window or graphics
+// context (e.g. visual regression tests on a headless CI server).
+//
+// The renderer is ticked until the view is ready and has painted, then until a
+// tick produces no further changes, so content that settles over a few frames
+// is captured. Returns an error if nothing is painted within RenderOnceTimeout.
+//
+// RenderOnce ticks the shared renderer, so don't call it concurrently with
+// Update on other views.
+func RenderOnce(width, height int, html []byte, opts *Options) (*image.RGBA, error) {
+	baseDir, debug := resolveOpts(opts)
+	if err := initBridge(bridgePath(opts, baseDir)); err != nil {
+		return nil, fmt.Errorf("bridge: %w", err)
+	}
+	if err := ensureULInit(baseDir, debug); err != nil {
+		return nil, err
+	}
+	ui, err := newUI(width, height, html, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer ui.Close()
+
+	if !ui.waitForPaint(RenderOnceTimeout) {
+		return nil, errors.New("ultralightui: RenderOnce timed out before the first paint")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	copy(img.Pix, ui.pixels)
+	return img, nil
+}
+
+// waitForPaint ticks the renderer until the view is ready and has painted into
+// ui.pixels, then until a tick brings no further changes. Returns false if
+// nothing was painted before the timeout.
+func (ui *UltralightUI) waitForPaint(timeout time.Duration) bool {
+	painted := false
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		ulTick()
+		if ulViewIsReady(ui.viewID) == 0 {
+			time.Sleep(renderOnceInterval)
+			continue
+		}
+		if ui.copyFrame() {
+			painted = true
+		} else if painted {
+			break // settled: no changes since the last paint
+		}
+		time.Sleep(renderOnceInterval)
+	}
+	return painted
+}
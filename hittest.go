@@ -0,0 +1,110 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "fmt"
+
+// SetHitRect restricts where the view accepts mouse, scroll and touch input to
+// a screen rectangle, independently of the bounds set with SetBounds (which
+// still define where the view is drawn and how coordinates map to it). Use it
+// for a full-window view whose only interactive part is a panel, so clicks
+// elsewhere reach the game. (0,0,0,0) removes the restriction.
+func (ui *UltralightUI) SetHitRect(x, y, w, h int) {
+	ui.hitX, ui.hitY, ui.hitW, ui.hitH = x, y, w, h
+}
+
+// SetClickThroughAlpha lets input through to whatever is below the view where
+// its pixels have an alpha below threshold, so an irregularly shaped overlay
+// (e.g. a speech bubble) only captures clicks on its visible parts; the view
+// then neither receives those clicks nor takes focus from them. 0 disables it.
+// Pixels are tested in the last copied frame, which is the one on screen when
+// the input is handled, inside the hit rect and bounds.
+func (ui *UltralightUI) SetClickThroughAlpha(threshold uint8) {
+	ui.clickThroughAlpha = threshold
+}
+
+// SetAlphaHitTest makes fully transparent pixels let input through; it is
+// SetClickThroughAlpha(1) when enabled and SetClickThroughAlpha(0) otherwise.
+func (ui *UltralightUI) SetAlphaHitTest(enabled bool) {
+	if enabled {
+		ui.SetClickThroughAlpha(1)
+	} else {
+		ui.SetClickThroughAlpha(0)
+	}
+}
+
+// SetClickThroughEmpty lets a click through to the game when it lands on no
+// element of the page, only on its <body> or <html>, so a full-window HUD with
+// mostly empty HTML doesn't swallow clicks meant for the world below it. Such
+// a press reaches neither the page nor the focus, like a click outside the
+// bounds, while hover and mouse moves carry on as usual. Each press in the
+// view then costs an EvalSync round trip to the bridge, so it is off by default.
+func (ui *UltralightUI) SetClickThroughEmpty(enabled bool) {
+	ui.clickThroughEmpty = enabled
+}
+
+// emptyHitScript returns the JS reporting "1" when the view pixel x, y is
+// over no element but <body> or <html>.
+func emptyHitScript(x, y int) string {
+	return fmt.Sprintf("(function(){var r=window.devicePixelRatio||1,"+
+		"e=document.elementFromPoint(%d/r,%d/r);"+
+		"return !e||e===document.body||e===document.documentElement?'1':'0';})()", x, y)
+}
+
+// emptyAt reports whether the page has no element but <body> or <html> at
+// view coordinates x, y. It is false until the DOM is ready and when the
+// hit test fails, so the click is then handled by the view as usual.
+func (ui *UltralightUI) emptyAt(x, y int) bool {
+	if !ui.domReady {
+		return false
+	}
+	res, err := ui.EvalSync(emptyHitScript(x, y))
+	return err == nil && res == "1"
+}
+
+// passEmptyPress reports whether the buttons just pressed at offset-adjusted
+// screen coordinates mx, my land on an empty page area with
+// SetClickThroughEmpty on. It then marks them as pressed outside, so the view
+// ignores them until released, without leaving the view.
+func (ui *UltralightUI) passEmptyPress(mx, my int, left, right, middle bool) bool {
+	if !ui.clickThroughEmpty || !(left || right || middle) || ui.anyButtonDown() ||
+		!ui.emptyAt(ui.viewCoords(mx, my)) {
+		return false
+	}
+	ui.leftOutside = ui.leftOutside || left
+	ui.rightOutside = ui.rightOutside || right
+	ui.middleOutside = ui.middleOutside || middle
+	return true
+}
+
+// hitTest reports whether the view takes input at offset-adjusted screen
+// coordinates: inside the bounds and the hit rect, and on a pixel at least as
+// opaque as the click-through threshold.
+func (ui *UltralightUI) hitTest(mx, my int) bool {
+	if !ui.inBounds(mx, my) {
+		return false
+	}
+	if ui.hitW > 0 && ui.hitH > 0 &&
+		(mx < ui.hitX || mx >= ui.hitX+ui.hitW || my < ui.hitY || my >= ui.hitY+ui.hitH) {
+		return false
+	}
+	if ui.clickThroughAlpha > 0 {
+		x, y := ui.viewCoords(mx, my)
+		return ui.alphaAt(x, y) >= ui.clickThroughAlpha
+	}
+	return true
+}
+
+// alphaAt returns the alpha of the pixel at view coordinates x, y in the last
+// frame, or 0 outside the view or before the first frame.
+func (ui *UltralightUI) alphaAt(x, y int) uint8 {
+	if !ui.hasFrame || x < 0 || y < 0 || x >= ui.width || y >= ui.height {
+		return 0
+	}
+	i := (y*ui.width+x)*4 + 3
+	if i >= len(ui.pixels) {
+		return 0
+	}
+	return ui.pixels[i]
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+// Key event types for FireKey.
+const (
+	KeyEventRawKeyDown = 0 // key pressed; triggers accelerators, never inserts text
+	KeyEventKeyDown    = 1 // key pressed, with text
+	KeyEventKeyUp      = 2 // key released
+	KeyEventChar       = 3 // text input: text is the typed character(s), vk is 0
+)
+
+// Key modifier bits for FireKey.
+const (
+	KeyModAlt   = 1
+	KeyModCtrl  = 2
+	KeyModMeta  = 4
+	KeyModShift = 8
+)
+
+// FireKey sends a synthetic key event to the page, as if typed while the view
+// had focus (it is delivered whether or not the view has keyboard focus).
+// keyType is one of the KeyEvent* constants, vk a Windows virtual key code
+// (e.g. 0x0D for Enter), mods a combination of KeyMod* bits, and text the
+// character(s) for KeyEventChar. The keyboard path sends RawKeyDown and KeyUp
+// for every key and Char for printable text; to press Enter in a form:
+//
+//	ui.FireKey(ultralightui.KeyEventRawKeyDown, 0x0D, 0, "\r")
+//	ui.FireKey(ultralightui.KeyEventKeyUp, 0x0D, 0, "\r")
+func (ui *UltralightUI) FireKey(keyType int, vk int32, mods uint32, text string) {
+	if ui.closed.Load() {
+		return
+	}
+	ulViewFireKey(ui.viewID, int32(keyType), vk, mods, text)
+}
+
+// Mouse event types for FireMouse (ULMouseEventType).
+const (
+	MouseEventMoved = 0
+	MouseEventDown  = 1
+	MouseEventUp    = 2
+)
+
+// Mouse buttons for FireMouse (ULMouseButton).
+const (
+	MouseButtonNone   = 0
+	MouseButtonLeft   = 1
+	MouseButtonMiddle = 2
+	MouseButtonRight  = 3
+)
+
+// FireMouse sends a synthetic mouse event to the page, e.g. for scripted UI
+// tests or input from a non-Ebiten source. x, y are view-local coordinates
+// (0,0 is the view's top-left pixel), not screen coordinates: bounds, cursor
+// offsets and mouse scale are not applied. eventType is one of the MouseEvent*
+// constants and button one of the MouseButton* constants (MouseButtonNone for
+// moves without a button held). A click is a Down followed by an Up.
+func (ui *UltralightUI) FireMouse(eventType int, x, y int, button int) {
+	if ui.closed.Load() {
+		return
+	}
+	ulViewFireMouse(ui.viewID, int32(eventType), int32(x), int32(y), int32(button))
+}
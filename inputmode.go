@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+// InputMode selects which input a view takes from Ebiten (see SetInputMode).
+type InputMode int
+
+const (
+	// InputDefault forwards mouse and wheel while the cursor is in the view's
+	// bounds, and keyboard while it has keyboard focus, which a click gives it.
+	InputDefault InputMode = iota
+	// InputMouseOnly forwards mouse moves and buttons only: no wheel, no
+	// keyboard, and clicks don't take keyboard focus, e.g. for a minimap
+	// with clickable markers.
+	InputMouseOnly
+	// InputMouseAndScroll is InputMouseOnly plus the wheel.
+	InputMouseAndScroll
+	// InputFull forwards mouse and wheel like InputDefault, and keyboard
+	// while the cursor is over the view as well as while it is focused,
+	// without clicks taking keyboard focus from another view. A hovered view
+	// and the focused one both get the keys.
+	InputFull
+)
+
+// SetInputMode selects which input the view takes, beyond the single keyboard
+// focus: e.g. an overlay scrolled with the wheel or arrow keys while the
+// cursor is over it, leaving keyboard focus with the main view. BlockInput
+// and SetBounds still apply in every mode.
+func (ui *UltralightUI) SetInputMode(mode InputMode) {
+	ui.inputMode = mode
+}
+
+// InputMode returns the mode set with SetInputMode.
+func (ui *UltralightUI) InputMode() InputMode {
+	return ui.inputMode
+}
+
+// takesFocusOnClick reports whether a click inside the view gives it keyboard focus.
+func (ui *UltralightUI) takesFocusOnClick() bool {
+	return ui.inputMode == InputDefault
+}
+
+// takesScroll reports whether wheel input over the view is forwarded.
+func (ui *UltralightUI) takesScroll() bool {
+	return ui.inputMode != InputMouseOnly
+}
+
+// takesKeyboard reports whether this frame's keys go to the view; inBounds is
+// whether the cursor is over it.
+func (ui *UltralightUI) takesKeyboard(inBounds bool) bool {
+	switch ui.inputMode {
+	case InputMouseOnly, InputMouseAndScroll:
+		return false
+	case InputFull:
+		return inBounds || getFocusedViewID() == ui.viewID
+	default:
+		return getFocusedViewID() == ui.viewID
+	}
+}
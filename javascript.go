@@ -0,0 +1,26 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "errors"
+
+// ErrJavaScriptDisabled is returned by EvalSync and the DOM queries built on
+// it for views created with Options.DisableJavaScript.
+var ErrJavaScriptDisabled = errors.New("ultralightui: JavaScript is disabled in this view")
+
+// jsToggleBridge is the first bridge version honouring viewFlagDisableJavaScript;
+// older bridges would ignore the flag and run the page's scripts.
+const jsToggleBridge = "1.16.0"
+
+// checkJavaScript reports whether the loaded bridge can create a view with
+// JavaScript disabled, when opts asks for it.
+func checkJavaScript(opts *Options) error {
+	if opts == nil || !opts.DisableJavaScript {
+		return nil
+	}
+	if !bridgeAtLeast(jsToggleBridge) {
+		return errUnsupported("DisableJavaScript (bridge " + jsToggleBridge + ")")
+	}
+	return nil
+}
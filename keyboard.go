@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// KeyMapping is the logical key produced by a physical key on a given layout.
+type KeyMapping struct {
+	VK   int32  // Windows virtual key code (e.g. 0x5A for Z)
+	Char string // unmodified character, used by Ultralight to match shortcuts
+}
+
+// KeyboardLayout maps physical Ebiten keys (named after their US QWERTY
+// position) to the virtual key and unmodified character of the user's layout.
+// Keys not present in the map use the built-in US mapping.
+type KeyboardLayout map[ebiten.Key]KeyMapping
+
+// Predefined layouts. Only keys that differ from US QWERTY are listed.
+var (
+	// LayoutUS is the built-in US QWERTY mapping.
+	LayoutUS KeyboardLayout = nil
+
+	// LayoutAZERTY is the French AZERTY layout (letter keys).
+	LayoutAZERTY = KeyboardLayout{
+		ebiten.KeyQ:         {0x41, "a"},
+		ebiten.KeyA:         {0x51, "q"},
+		ebiten.KeyW:         {0x5A, "z"},
+		ebiten.KeyZ:         {0x57, "w"},
+		ebiten.KeySemicolon: {0x4D, "m"},
+		ebiten.KeyM:         {0xBC, ","},
+	}
+
+	// LayoutQWERTZ is the German QWERTZ layout (letter keys).
+	LayoutQWERTZ = KeyboardLayout{
+		ebiten.KeyY: {0x5A, "z"},
+		ebiten.KeyZ: {0x59, "y"},
+	}
+)
+
+// keyboardLayout is the active layout. Like the other input globals, it is
+// only read from Ebiten's Update loop.
+var keyboardLayout KeyboardLayout
+
+// SetKeyboardLayout sets the layout used to translate physical keys into the
+// virtual keys and unmodified text sent to Ultralight, so accelerators such as
+// Ctrl+Z match the key labelled Z on the user's keyboard. nil restores US QWERTY.
+// Typed text is unaffected: it always comes from the OS text input system.
+func SetKeyboardLayout(m KeyboardLayout) {
+	keyboardLayout = m
+}
+
+// mapKey returns the virtual key code and unmodified character for a physical
+// key according to the active keyboard layout.
+func mapKey(key ebiten.Key) (int32, string) {
+	if m, ok := keyboardLayout[key]; ok {
+		return m.VK, m.Char
+	}
+	vk := ebitenKeyToVK(key)
+	return vk, vkToChar(vk)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "bytes"
+
+// loadCounters counts the main-frame loads the bridge has seen start, finish
+// and fail (ul_view_get_load_state).
+type loadCounters struct {
+	starts, finishes, fails int
+}
+
+// loadEvent is one main-frame load transition, in the order it happened.
+type loadEvent int
+
+const (
+	loadStarted loadEvent = iota
+	loadFinished
+	loadFailed
+)
+
+// loadState returns the view's load progress in 0..1 and its load counters.
+// Bridges without ul_view_get_load_state report 1 once the view is ready.
+func loadState(viewID int32) (float64, loadCounters) {
+	if ulViewGetLoadState == nil {
+		if ulViewIsReady(viewID) != 0 {
+			return 1, loadCounters{}
+		}
+		return 0, loadCounters{}
+	}
+	var s [4]int32
+	ulViewGetLoadState(viewID, &s[0]) // pointer arg: s escapes, can't move mid-call
+	return float64(s[0]) / 1000, loadCounters{int(s[1]), int(s[2]), int(s[3])}
+}
+
+// resetPageHelpers re-arms the helper scripts after a new page has loaded,
+// since a navigation discards the previous page's scripts. The helpers are
+// idempotent, so re-injecting into the same page is harmless.
+func (ui *UltralightUI) resetPageHelpers() {
+	ui.downloadHelperInjected = false
+	ui.overflowHelperInjected = false
+	ui.navigationHelperInjected = false
+	ui.resourceHelperInjected = false
+	ui.localeApplied = false // dir and lang belong to the old document
+	ui.fontGen = 0
+	ui.modsApplied = false
+	ui.animHelperInjected = false
+	ui.animKnown = false // until the new page reports
+	if ui.goHelperInjected && !ui.disableEditHelpers {
+		ui.injectGoHelper()
+	}
+}
+
+// loadError returns the URL, description and code of the view's last failed
+// load. Several failures between two Updates all report the last one.
+func loadError(viewID int32) (url, description string, code int) {
+	if ulViewGetLoadError == nil {
+		return "", "", 0
+	}
+	var u [512]byte
+	var d [256]byte
+	c := ulViewGetLoadError(viewID, &u[0], int32(len(u)), &d[0], int32(len(d)))
+	return cString(u[:]), cString(d[:]), int(c)
+}
+
+// cString returns the NUL-terminated string at the start of b.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// loadEvents turns the counter changes between two polls into events. Loads
+// never overlap, so a load still open at prev ends before the next one starts.
+func loadEvents(prev, cur loadCounters) []loadEvent {
+	finished, failed := cur.finishes-prev.finishes, cur.fails-prev.fails
+	var events []loadEvent
+	end := func() {
+		if finished > 0 {
+			finished--
+			events = append(events, loadFinished)
+		} else if failed > 0 {
+			failed--
+			events = append(events, loadFailed)
+		}
+	}
+	if prev.starts > prev.finishes+prev.fails {
+		end()
+	}
+	for i := prev.starts; i < cur.starts; i++ {
+		events = append(events, loadStarted)
+		end()
+	}
+	return events
+}
+
+// LoadProgress returns the main frame's load progress, from 0 to 1. Ultralight
+// reports load milestones, not bytes, so it moves in steps: 0.1 when a load
+// begins, 0.7 when the DOM is ready and 1 when loading finishes or fails.
+// HTML and VFS pages usually jump straight to 1. Returns 0 once closed.
+func (ui *UltralightUI) LoadProgress() float64 {
+	if ui.closed.Load() {
+		return 0
+	}
+	p, _ := loadState(ui.viewID)
+	return p
+}
+
+// pollLoadEvents calls OnLoadStart, OnLoadError and OnLoadFinish for the
+// loads that began, failed or ended since the last Update.
+func (ui *UltralightUI) pollLoadEvents() {
+	_, cur := loadState(ui.viewID)
+	prev := ui.loads
+	ui.loads = cur
+	for _, ev := range loadEvents(prev, cur) {
+		switch ev {
+		case loadStarted:
+			if ui.OnLoadStart != nil {
+				ui.OnLoadStart()
+			}
+		case loadFailed:
+			if ui.OnLoadError != nil || ui.logger != nil {
+				url, desc, code := loadError(ui.viewID)
+				ui.logLoadError(url, desc, code)
+				if ui.OnLoadError != nil {
+					ui.OnLoadError(url, desc, code)
+				}
+			}
+			fallthrough
+		case loadFinished:
+			ui.resetPageHelpers()
+			if ui.OnLoadFinish != nil {
+				ui.OnLoadFinish()
+			}
+		}
+	}
+}
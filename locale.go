@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SetTextDirection sets the page's base text direction, the dir attribute of
+// the root element: "ltr", "rtl" or "auto" (from the first strong character).
+// It drives bidi layout and CSS logical properties (margin-inline-start,
+// text-align: start, ...). Empty restores the page's own dir; other values
+// are ignored. The page's lang is left alone. Like SetLocale it can be set
+// right after creating the view and is reapplied to every page the view loads.
+func (ui *UltralightUI) SetTextDirection(dir string) {
+	dir = strings.ToLower(strings.TrimSpace(dir))
+	switch dir {
+	case "", "ltr", "rtl", "auto":
+	default:
+		return
+	}
+	ui.textDir, ui.textDirSet = dir, true
+	ui.applyLocaleSoon()
+}
+
+// SetLocale sets the page's language, the lang attribute of the root element,
+// e.g. "ar" or "pt-BR", which :lang() selectors and quotes follow. Empty
+// restores the page's own lang; its dir is left alone. Ultralight has no
+// locale setting of its own: shaping (Arabic joining, bidi) works for any
+// locale, as long as a font with the script's glyphs is available.
+// navigator.language is not changed.
+func (ui *UltralightUI) SetLocale(locale string) {
+	ui.locale, ui.localeSet = strings.TrimSpace(locale), true
+	ui.applyLocaleSoon()
+}
+
+// TextDirection returns the direction set with SetTextDirection ("" if none).
+func (ui *UltralightUI) TextDirection() string { return ui.textDir }
+
+// Locale returns the language set with SetLocale ("" if none).
+func (ui *UltralightUI) Locale() string { return ui.locale }
+
+func (ui *UltralightUI) applyLocaleSoon() {
+	ui.localeApplied = false
+	if ui.domReady && !ui.closed.Load() {
+		ui.applyLocale()
+	}
+}
+
+// localeScript sets dir and lang on the document root, skipping a null
+// argument. The first write saves the page's own value in data-ul-orig-<attr>
+// ("=value", or "" if it had none) and an empty value puts it back.
+const localeScript = `(function(d,l){var e=document.documentElement;if(!e)return;
+function set(a,v){if(v===null)return;var k='data-ul-orig-'+a;
+if(v){if(!e.hasAttribute(k))e.setAttribute(k,e.hasAttribute(a)?'='+e.getAttribute(a):'');e.setAttribute(a,v);return}
+if(!e.hasAttribute(k))return;var o=e.getAttribute(k);e.removeAttribute(k);
+if(o)e.setAttribute(a,o.slice(1));else e.removeAttribute(a)}
+set('dir',d);set('lang',l)})`
+
+// applyLocale applies the dir and lang set with SetTextDirection and
+// SetLocale to the document root, leaving an attribute never set untouched.
+func (ui *UltralightUI) applyLocale() {
+	dir, lang := []byte("null"), []byte("null")
+	if ui.textDirSet {
+		dir, _ = json.Marshal(ui.textDir)
+	}
+	if ui.localeSet {
+		lang, _ = json.Marshal(ui.locale)
+	}
+	ui.Eval(localeScript + "(" + string(dir) + "," + string(lang) + ")")
+	ui.localeApplied = true
+}
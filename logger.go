@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Ultralight's ULMessageLevel values, as queued with console messages.
+const (
+	consoleLog     = 1
+	consoleWarning = 2
+	consoleError   = 3
+	consoleDebug   = 4
+	consoleInfo    = 5
+)
+
+// consoleLevel maps a ULMessageLevel to the slog level it is logged at.
+func consoleLevel(level int32) slog.Level {
+	switch level {
+	case consoleWarning:
+		return slog.LevelWarn
+	case consoleError:
+		return slog.LevelError
+	case consoleDebug:
+		return slog.LevelDebug
+	default: // consoleLog, consoleInfo
+		return slog.LevelInfo
+	}
+}
+
+// pollConsole drains the JS console messages the bridge queued for the view
+// into Options.Logger. Without a Logger they are dropped, so the bridge's
+// queue doesn't grow for the life of the view.
+func (ui *UltralightUI) pollConsole() {
+	for {
+		msg, level, ok := pollConsoleMessage(ui.viewID)
+		if !ok {
+			return
+		}
+		if ui.logger != nil {
+			ui.logger.Log(context.Background(), consoleLevel(level), msg, "view", ui.viewID, "source", "console")
+		}
+	}
+}
+
+// logLoadError reports a failed main-frame load to Options.Logger.
+func (ui *UltralightUI) logLoadError(url, description string, code int) {
+	if ui.logger == nil {
+		return
+	}
+	ui.logger.Warn("load failed", "view", ui.viewID, "url", url, "description", description, "code", code)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MemoryUsage returns an approximation of the native memory the bridge holds
+// for this view: the Ultralight surface bitmap plus queued messages, scripts
+// and binary payloads. Ultralight exposes no per-view heap stats, so DOM, JS
+// and cache memory are not included; the Go-side frame buffers (width*height*4
+// bytes, twice with DoubleBuffer) aren't either, since the GC reclaims them.
+// A number that keeps growing on an idle view usually means queued messages
+// nobody reads (Update not called).
+func (ui *UltralightUI) MemoryUsage() (int64, error) {
+	if ui.closed.Load() {
+		return 0, ErrClosed
+	}
+	if ulViewMemoryUsage == nil {
+		return 0, errUnsupported("ul_view_memory_usage")
+	}
+	n := ulViewMemoryUsage(ui.viewID)
+	if n < 0 {
+		return 0, fmt.Errorf("MemoryUsage: bridge has no view %d", ui.viewID)
+	}
+	return n, nil
+}
+
+// TotalMemoryUsage returns the MemoryUsage approximation summed over all open
+// views, plus the files registered in the virtual file system. Comparing it
+// before and after creating and closing transient views (tooltips, popups)
+// shows whether their native memory is released.
+func TotalMemoryUsage() (int64, error) {
+	if ulInit == nil {
+		return 0, errors.New("ultralightui: bridge not loaded (call Preload first)")
+	}
+	if ulMemoryUsageTotal == nil {
+		return 0, errUnsupported("ul_memory_usage_total")
+	}
+	return ulMemoryUsageTotal(), nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// defaultMessageHandler receives go.send messages from views without OnMessage.
+var defaultMessageHandler atomic.Pointer[func(ui *UltralightUI, msg string)]
+
+// SetDefaultMessageHandler sets a handler for go.send messages from any view
+// that has no OnMessage of its own; ui is the view that sent msg. nil removes it.
+func SetDefaultMessageHandler(fn func(ui *UltralightUI, msg string)) {
+	if fn == nil {
+		defaultMessageHandler.Store(nil)
+		return
+	}
+	defaultMessageHandler.Store(&fn)
+}
+
+// dispatchMessage delivers a page message to OnMessage, or else to the default
+// handler. With Options.StrictMessaging, a message nobody handles is reported:
+// logged once per view, or a panic when Options.Debug is also set.
+func (ui *UltralightUI) dispatchMessage(msg string) {
+	if ui.OnMessage != nil {
+		ui.OnMessage(msg)
+		return
+	}
+	if h := defaultMessageHandler.Load(); h != nil {
+		(*h)(ui, msg)
+		return
+	}
+	if !ui.strictMessaging {
+		return
+	}
+	if len(msg) > 200 {
+		msg = msg[:200] + "..."
+	}
+	text := fmt.Sprintf("ultralightui: view %d dropped go.send message with no OnMessage or default handler: %s", ui.viewID, msg)
+	if ui.debug {
+		panic(text)
+	}
+	if !ui.unhandledLogged {
+		ui.unhandledLogged = true
+		log.Print(text)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "strconv"
+
+// modifierState is the set of modifier keys pushed with SetModifierState.
+type modifierState struct {
+	shift, ctrl, alt, meta bool
+}
+
+// SetModifierState pushes which modifier keys are held to the page, whether
+// or not the view has keyboard focus, e.g. to show item comparison tooltips
+// while Shift is held. The page reads window.go.modifiers
+// ({shift, ctrl, alt, meta}) or listens for it to change:
+//
+//	window.addEventListener('modifierchange', e => compare.hidden = !e.detail.shift)
+//
+// Call it every frame from Update; only changes reach the page:
+//
+//	ui.SetModifierState(ebiten.IsKeyPressed(ebiten.KeyShift), ebiten.IsKeyPressed(ebiten.KeyControl),
+//		ebiten.IsKeyPressed(ebiten.KeyAlt), ebiten.IsKeyPressed(ebiten.KeyMeta))
+//
+// The state is set once the DOM is ready and again on every page the view loads.
+func (ui *UltralightUI) SetModifierState(shift, ctrl, alt, meta bool) {
+	m := modifierState{shift, ctrl, alt, meta}
+	if ui.modsSet && m == ui.mods {
+		return
+	}
+	ui.mods, ui.modsSet, ui.modsApplied = m, true, false
+	if ui.domReady && !ui.closed.Load() {
+		ui.applyModifiers()
+	}
+}
+
+// applyModifiers sets window.go.modifiers and fires modifierchange.
+func (ui *UltralightUI) applyModifiers() {
+	m := ui.mods
+	state := `{shift:` + strconv.FormatBool(m.shift) + `,ctrl:` + strconv.FormatBool(m.ctrl) +
+		`,alt:` + strconv.FormatBool(m.alt) + `,meta:` + strconv.FormatBool(m.meta) + `}`
+	ui.Eval(`(function(m){if(window.go)window.go.modifiers=m;` +
+		`window.dispatchEvent(new CustomEvent('modifierchange',{detail:m}))})(` + state + `)`)
+	ui.modsApplied = true
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+// History ops for ul_view_navigate (NAV_* in ul_bridge.c).
+const (
+	navBack = iota
+	navForward
+	navCanBack
+	navCanForward
+	navStop
+)
+
+// LoadErrorStopped is the OnLoadError code of a load aborted by Stop
+// (WebKit's cancelled-load code).
+const LoadErrorStopped = -999
+
+// navigate runs a history op, or returns -3 (like the bridge for a missing
+// SDK API) when the bridge lacks ul_view_navigate.
+func navigate(viewID int32, op int32) int32 {
+	if ulViewNavigate == nil {
+		return -3
+	}
+	return ulViewNavigate(viewID, op)
+}
+
+// GoBack navigates to the previous page in the view's history, like a
+// browser's back button. It does nothing if CanGoBack is false. The page
+// loads during the next Updates, firing OnLoadStart and OnLoadFinish.
+func (ui *UltralightUI) GoBack() {
+	if ui.closed.Load() {
+		return
+	}
+	navigate(ui.viewID, navBack)
+}
+
+// GoForward navigates to the next page in the view's history, undoing a
+// GoBack. It does nothing if CanGoForward is false.
+func (ui *UltralightUI) GoForward() {
+	if ui.closed.Load() {
+		return
+	}
+	navigate(ui.viewID, navForward)
+}
+
+// CanGoBack reports whether the view has a previous page to go back to.
+// It is false for closed views and SDKs without the history API.
+func (ui *UltralightUI) CanGoBack() bool {
+	if ui.closed.Load() {
+		return false
+	}
+	return navigate(ui.viewID, navCanBack) == 1
+}
+
+// CanGoForward reports whether the view has a next page to go forward to.
+func (ui *UltralightUI) CanGoForward() bool {
+	if ui.closed.Load() {
+		return false
+	}
+	return navigate(ui.viewID, navCanForward) == 1
+}
+
+// Stop aborts the view's in-flight page load, e.g. a slow or hung network
+// fetch. If a load was in progress, the next Update calls OnLoadError with
+// LoadErrorStopped and then OnLoadFinish. Content already parsed stays.
+func (ui *UltralightUI) Stop() {
+	if ui.closed.Load() {
+		return
+	}
+	navigate(ui.viewID, navStop)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// injectNavigationHelper installs the script behind OnNavigationRequest.
+// Ultralight's C API has no navigation policy callback, so link clicks are
+// intercepted in JS: the listener runs last (window, bubble phase), leaving
+// clicks the page already handled (preventDefault, e.g. SPA routers) alone,
+// and skips <a download>, javascript: links and same-document #fragments.
+// The URL goes to Go as __navigate, which loads it if the callback allows.
+func (ui *UltralightUI) injectNavigationHelper() {
+	ui.Eval(`(function(){
+if(window.__ulNavInit)return;window.__ulNavInit=1;
+window.addEventListener('click',function(ev){
+if(ev.defaultPrevented||ev.button!==0)return;
+var a=ev.target;while(a&&a.tagName!=='A')a=a.parentElement;
+if(!a||!a.href||a.hasAttribute('download'))return;
+var u=a.href;if(/^javascript:/i.test(u))return;
+if(u.indexOf('#')>=0&&u.split('#')[0]===location.href.split('#')[0])return;
+ev.preventDefault();
+if(window.go&&window.go.send)window.go.send({action:'__navigate',url:u});
+},false);
+})();`)
+}
+
+// handleNavigateMsg intercepts __navigate messages sent by the navigation
+// helper, asks OnNavigationRequest and performs the navigation if allowed.
+// Returns true if the message was consumed (caller should skip OnMessage).
+func (ui *UltralightUI) handleNavigateMsg(msg string) bool {
+	if !strings.HasPrefix(msg, "{\"action\":\"__navigate\"") {
+		return false
+	}
+	var data struct {
+		Action string `json:"action"`
+		URL    string `json:"url"`
+	}
+	if json.Unmarshal([]byte(msg), &data) != nil || data.Action != "__navigate" {
+		return false
+	}
+	if ui.OnNavigationRequest != nil && !ui.OnNavigationRequest(data.URL) {
+		return true
+	}
+	u, _ := json.Marshal(data.URL)
+	ui.Eval("location.href=" + string(u))
+	return true
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// injectOverflowHelper installs an observer that reports when the document's
+// scroll size exceeds the viewport, per axis. It re-checks after DOM mutations,
+// window resizes and loads, and only sends __overflow when a flag changes.
+func (ui *UltralightUI) injectOverflowHelper() {
+	ui.Eval(`(function(){
+if(window.__ulOverflowInit)return;window.__ulOverflowInit=1;
+var lh=false,lv=false,first=true,pending=0;
+function check(){
+pending=0;var d=document.documentElement,b=document.body;if(!d)return;
+var sw=Math.max(d.scrollWidth,b?b.scrollWidth:0),sh=Math.max(d.scrollHeight,b?b.scrollHeight:0);
+var h=sw>d.clientWidth,v=sh>d.clientHeight;
+if(!first&&h===lh&&v===lv)return;
+first=false;lh=h;lv=v;
+if(window.go&&window.go.send)window.go.send({action:'__overflow',horizontal:h,vertical:v});
+}
+function schedule(){if(!pending)pending=setTimeout(check,0)}
+if(window.MutationObserver)new MutationObserver(schedule).observe(document.documentElement,{childList:true,subtree:true,attributes:true,characterData:true});
+window.addEventListener('resize',schedule);
+window.addEventListener('load',schedule,true);
+check();
+})();`)
+}
+
+// handleOverflowMsg intercepts __overflow messages sent by the overflow helper
+// and calls OnOverflow. Returns true if the message was consumed (caller should
+// skip OnMessage).
+func (ui *UltralightUI) handleOverflowMsg(msg string) bool {
+	if !strings.HasPrefix(msg, "{\"action\":\"__overflow\"") {
+		return false
+	}
+	var data struct {
+		Action     string `json:"action"`
+		Horizontal bool   `json:"horizontal"`
+		Vertical   bool   `json:"vertical"`
+	}
+	if json.Unmarshal([]byte(msg), &data) != nil || data.Action != "__overflow" {
+		return false
+	}
+	if ui.OnOverflow != nil {
+		ui.OnOverflow(data.Horizontal, data.Vertical)
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PostTo delivers data to another view's page, serialized to JSON, calling
+// window.go.onPeerMessage(data) there, or window.go.receive(data) if the page
+// defines no onPeerMessage. Use it to forward a message between views, e.g.
+// from a sidebar's OnMessage to the main view:
+//
+//	sidebar.OnMessage = func(msg string) { sidebar.PostTo(mainView, json.RawMessage(msg)) }
+//
+// The payload is run like Eval on the other view: after its pending coalesced
+// Sends, and queued until its DOM is ready. Returns ErrClosed if either view is closed.
+func (ui *UltralightUI) PostTo(other *UltralightUI, data interface{}) error {
+	if other == nil {
+		return errors.New("ultralightui: PostTo: nil view")
+	}
+	if ui.closed.Load() || other.closed.Load() {
+		return ErrClosed
+	}
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("PostTo: %w", err)
+	}
+	other.Eval("(function(d){var g=window.go;if(!g)return;" +
+		"if(typeof g.onPeerMessage==='function')g.onPeerMessage(d);" +
+		"else if(typeof g.receive==='function')g.receive(d);})(" + string(jsonBytes) + ");")
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// pinchMinStep is the smallest relative distance change reported as a pinch,
+// so finger jitter doesn't re-apply the zoom every frame.
+const pinchMinStep = 0.01
+
+// pinchTracker follows a two-finger gesture across frames.
+type pinchTracker struct {
+	ids      [2]ebiten.TouchID
+	active   bool
+	lastDist float64
+}
+
+// update takes the two current touches and returns the scale since the last
+// reported step, or false if the gesture just started or barely changed.
+func (p *pinchTracker) update(ids [2]ebiten.TouchID, a, b image.Point) (float64, bool) {
+	dist := math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+	if !p.active || p.ids != ids || p.lastDist < 1 {
+		p.active, p.ids, p.lastDist = true, ids, dist
+		return 0, false
+	}
+	scale := dist / p.lastDist
+	if math.Abs(scale-1) < pinchMinStep {
+		return 0, false
+	}
+	p.lastDist = dist
+	return scale, true
+}
+
+func (p *pinchTracker) reset() {
+	p.active = false
+}
+
+// forwardPinch turns a two-finger pinch that started inside the bounds into
+// OnPinch calls, or into SetZoom when OnPinch is nil. Other touch counts end
+// the gesture; single touches are left alone.
+func (ui *UltralightUI) forwardPinch(blocked bool) {
+	ui.touchBuf = ebiten.AppendTouchIDs(ui.touchBuf[:0])
+	if len(ui.touchBuf) != 2 || blocked {
+		ui.pinch.reset()
+		return
+	}
+	var pts [2]image.Point
+	for i, id := range ui.touchBuf {
+		x, y := ebiten.TouchPosition(id)
+		pts[i] = image.Pt(x-GlobalCursorOffsetX, y-GlobalCursorOffsetY)
+		if !ui.pinch.active && !ui.hitTest(pts[i].X, pts[i].Y) {
+			return // both fingers must start on the view
+		}
+	}
+	scale, ok := ui.pinch.update([2]ebiten.TouchID{ui.touchBuf[0], ui.touchBuf[1]}, pts[0], pts[1])
+	if !ok {
+		return
+	}
+	if ui.OnPinch != nil {
+		cx, cy := ui.toLocal((pts[0].X+pts[1].X)/2, (pts[0].Y+pts[1].Y)/2)
+		ui.OnPinch(scale, cx, cy)
+		return
+	}
+	ui.SetZoom(ui.GetZoom() * scale)
+}
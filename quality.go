@@ -0,0 +1,118 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "time"
+
+// QualityMode controls how often a view refreshes its texture.
+type QualityMode int
+
+const (
+	// QualityFull ticks and copies pixels every frame (default).
+	QualityFull QualityMode = iota
+	// QualityLow refreshes the texture every lowQualityDivisor frames.
+	// Messages and input are still processed every frame.
+	QualityLow
+	// QualityAdaptive behaves like QualityFull while the view's average Update
+	// cost stays within the frame budget, and drops to QualityLow while it exceeds it.
+	QualityAdaptive
+)
+
+// DefaultFrameBudget is the per-view Update cost QualityAdaptive tries to hold.
+const DefaultFrameBudget = 4 * time.Millisecond
+
+const (
+	lowQualityDivisor  = 2 // QualityLow refreshes 1 of every N frames
+	frameCostSmoothing = 8 // moving average weight: avg += (sample-avg)/N
+)
+
+// SetQualityMode sets how often this view refreshes its texture.
+// See QualityFull, QualityLow and QualityAdaptive.
+func (ui *UltralightUI) SetQualityMode(mode QualityMode) {
+	ui.quality = mode
+	if mode != QualityAdaptive {
+		ui.throttled = false
+	}
+}
+
+// SetFrameBudget sets the average Update cost QualityAdaptive tries to hold
+// for this view. d <= 0 restores DefaultFrameBudget.
+func (ui *UltralightUI) SetFrameBudget(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	ui.frameBudget = d
+}
+
+// IsThrottled returns true if the view is currently refreshing at reduced
+// frequency (QualityLow, or QualityAdaptive over budget).
+func (ui *UltralightUI) IsThrottled() bool {
+	return ui.quality == QualityLow || (ui.quality == QualityAdaptive && ui.throttled)
+}
+
+func (ui *UltralightUI) getFrameBudget() time.Duration {
+	if ui.frameBudget > 0 {
+		return ui.frameBudget
+	}
+	return DefaultFrameBudget
+}
+
+// SetUpdateInterval caps how often Update ticks Ultralight and refreshes the
+// texture, independently of the game's TPS: calls in between only poll
+// messages and forward input. E.g. a 120 TPS game with a UI that needs 30 FPS:
+//
+//	ui.SetUpdateInterval(time.Second / 30)
+//
+// With UpdateNoTick only the pixel copy is skipped; the shared Tick is up to
+// the caller. d <= 0 refreshes on every call (default).
+func (ui *UltralightUI) SetUpdateInterval(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	ui.updateInterval = d
+	ui.nextRefresh = time.Time{}
+}
+
+// refreshDue reports whether an Update at now ticks and copies under
+// SetUpdateInterval. Refreshes are scheduled a fixed interval apart, so
+// timing jitter doesn't lower the average rate; after a stall the schedule
+// restarts from now instead of catching up.
+func (ui *UltralightUI) refreshDue(now time.Time) bool {
+	if ui.updateInterval <= 0 {
+		return true
+	}
+	if now.Before(ui.nextRefresh) {
+		return false
+	}
+	ui.nextRefresh = ui.nextRefresh.Add(ui.updateInterval)
+	if !now.Before(ui.nextRefresh) {
+		ui.nextRefresh = now.Add(ui.updateInterval)
+	}
+	return true
+}
+
+// shouldSkipFrame reports whether frame n skips the renderer tick and pixel copy.
+func (ui *UltralightUI) shouldSkipFrame(n int) bool {
+	return ui.IsThrottled() && n%lowQualityDivisor != 0
+}
+
+// recordFrameCost feeds one Update duration into the moving average and, in
+// QualityAdaptive mode, switches throttling on above the budget and back off
+// below half of it (hysteresis avoids flapping around the threshold).
+func (ui *UltralightUI) recordFrameCost(d time.Duration) {
+	if ui.frameCost == 0 {
+		ui.frameCost = d
+	} else {
+		ui.frameCost += (d - ui.frameCost) / frameCostSmoothing
+	}
+	if ui.quality != QualityAdaptive {
+		return
+	}
+	budget := ui.getFrameBudget()
+	if !ui.throttled && ui.frameCost > budget {
+		ui.throttled = true
+	} else if ui.throttled && ui.frameCost < budget/2 {
+		ui.throttled = false
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+// NeedsRedraw reports whether the next Tick has work for the view: the last
+// tick painted it (e.g. a running animation), input or scripts are queued for
+// it, or its page is still loading. A host that free-runs (vsync off) can skip
+// Tick while no view needs it:
+//
+//	if ultralightui.AnyNeedsRedraw() || time.Since(lastTick) > 100*time.Millisecond {
+//		ultralightui.Tick()
+//		lastTick = time.Now()
+//	}
+//	for _, v := range views {
+//		v.UpdateNoTick()
+//	}
+//
+// JS timers (setTimeout, setInterval) only fire on ticks and are not
+// tracked, so keep ticking at some low rate as above. Closed and paused
+// views never need a redraw. Without ul_view_needs_paint in the bridge it
+// always reports true.
+func (ui *UltralightUI) NeedsRedraw() bool {
+	if ui.closed.Load() {
+		return false
+	}
+	if ulViewNeedsPaint == nil || !ui.domReady {
+		return true
+	}
+	return ulViewNeedsPaint(ui.viewID) != 0
+}
+
+// AnyNeedsRedraw reports whether any live view needs a redraw (see
+// NeedsRedraw), i.e. whether the host should call Tick this frame.
+func AnyNeedsRedraw() bool {
+	for _, v := range Views() {
+		if v.NeedsRedraw() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry of named views (SetName / ViewByName) and of every open view by
+// ID (ViewByID / Views).
+var (
+	registryMu  sync.Mutex
+	viewsByName = map[string]*UltralightUI{}
+	liveViews   = map[int32]*UltralightUI{}
+)
+
+// SetName registers the view under name so it can be retrieved with ViewByName
+// from anywhere in the program. Names are unique: setting a name already used
+// by another view moves it to this one. An empty name unregisters the view.
+func (ui *UltralightUI) SetName(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if ui.name != "" && viewsByName[ui.name] == ui {
+		delete(viewsByName, ui.name)
+	}
+	if prev := viewsByName[name]; prev != nil && prev != ui {
+		prev.name = ""
+	}
+	ui.name = name
+	if name != "" && !ui.closed.Load() {
+		viewsByName[name] = ui
+	}
+}
+
+// Name returns the name set with SetName.
+func (ui *UltralightUI) Name() string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return ui.name
+}
+
+// ViewByName returns the view registered under name with SetName, or nil if
+// there is none or it has been closed.
+func ViewByName(name string) *UltralightUI {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	ui := viewsByName[name]
+	if ui == nil || ui.closed.Load() {
+		return nil
+	}
+	return ui
+}
+
+// unregisterName removes the view from the name registry (on Close).
+func (ui *UltralightUI) unregisterName() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if ui.name != "" && viewsByName[ui.name] == ui {
+		delete(viewsByName, ui.name)
+	}
+}
+
+// registerLive adds a newly created view to the live views.
+func (ui *UltralightUI) registerLive() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	liveViews[ui.viewID] = ui
+}
+
+// unregisterLive removes the view from the live views (on Close).
+func (ui *UltralightUI) unregisterLive() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if liveViews[ui.viewID] == ui {
+		delete(liveViews, ui.viewID)
+	}
+}
+
+// ID returns the view's ID, as used by ViewByID. IDs are bridge view slots:
+// unique among open views, but reused by views created after a Close.
+func (ui *UltralightUI) ID() int32 {
+	return ui.viewID
+}
+
+// ViewByID returns the open view with the given ID.
+func ViewByID(id int32) (*UltralightUI, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	ui := liveViews[id]
+	if ui == nil || ui.closed.Load() {
+		return nil, false
+	}
+	return ui, true
+}
+
+// Views returns the open views in ID order. The slice is a snapshot: views
+// created or closed afterwards don't change it.
+func Views() []*UltralightUI {
+	registryMu.Lock()
+	views := make([]*UltralightUI, 0, len(liveViews))
+	for _, ui := range liveViews {
+		if !ui.closed.Load() {
+			views = append(views, ui)
+		}
+	}
+	registryMu.Unlock()
+	sort.Slice(views, func(i, j int) bool { return views[i].viewID < views[j].viewID })
+	return views
+}
+
+// Broadcast Sends data to every open view, in view ID order, e.g. for a
+// theme or locale change. data is serialized once. Like Send, call
+// it from the goroutine that runs Update: the registry is safe to read from
+// any goroutine, but each view's script queue is not. Views closed while
+// broadcasting are skipped.
+func Broadcast(data interface{}) error {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("Broadcast: %w", err)
+	}
+	for _, ui := range Views() {
+		ui.Send(json.RawMessage(jsonBytes)) // ErrClosed: closed meanwhile
+	}
+	return nil
+}
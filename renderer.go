@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "fmt"
+
+// Renderer selects how Ultralight renders views (Options.Renderer).
+type Renderer int
+
+const (
+	// RendererAuto picks the renderer the bridge supports best: today the CPU one.
+	RendererAuto Renderer = iota
+	// RendererCPU renders views into bitmaps on the CPU, which are uploaded
+	// to Ebiten images. It works without GPU access (RDP sessions, VMs,
+	// headless CI) and costs CPU time roughly proportional to the repainted area.
+	RendererCPU
+	// RendererGPU would render views with Ultralight's GPU driver. The bridge
+	// has none, so requesting it fails with ErrUnsupported instead of silently
+	// falling back to the CPU.
+	RendererGPU
+)
+
+// String returns "auto", "cpu" or "gpu".
+func (r Renderer) String() string {
+	switch r {
+	case RendererAuto:
+		return "auto"
+	case RendererCPU:
+		return "cpu"
+	case RendererGPU:
+		return "gpu"
+	}
+	return fmt.Sprintf("Renderer(%d)", int(r))
+}
+
+// checkRenderer reports whether the bridge can render with opts.Renderer.
+func checkRenderer(opts *Options) error {
+	if opts == nil {
+		return nil
+	}
+	switch opts.Renderer {
+	case RendererAuto, RendererCPU:
+		return nil
+	case RendererGPU:
+		return fmt.Errorf("%w: GPU renderer (the bridge renders views on the CPU)", ErrUnsupported)
+	}
+	return fmt.Errorf("ultralightui: unknown renderer %v", opts.Renderer)
+}
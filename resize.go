@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "fmt"
+
+// maxViewSide is the largest width or height ul_view_resize accepts.
+const maxViewSide = 0x7FFF
+
+// SetSize resizes the view to width by height pixels. The page re-lays out
+// (resize events, media queries) and the next Update copies a whole frame at
+// the new size. The texture is recreated at the new size by the next
+// GetTexture, so don't keep the old one. Bounds set with SetBounds are left
+// as they are. Needs a bridge with ul_view_resize.
+func (ui *UltralightUI) SetSize(width, height int) error {
+	if ui.closed.Load() {
+		return ErrClosed
+	}
+	if width <= 0 || height <= 0 || width > maxViewSide || height > maxViewSide {
+		return fmt.Errorf("%w: %dx%d", ErrInvalidSize, width, height)
+	}
+	if width == ui.width && height == ui.height {
+		return nil
+	}
+	if ulViewResize == nil {
+		return errUnsupported("ul_view_resize")
+	}
+	switch rc := ulViewResize(ui.viewID, int32(width), int32(height)); {
+	case rc == -3:
+		return errUnsupported("ulViewResize")
+	case rc < 0:
+		return fmt.Errorf("SetSize: bridge error %d", rc)
+	}
+	ui.resize(width, height)
+	ui.dirtyBuf, ui.drawBuf = nil, nil
+	if ui.texture != nil {
+		ui.texture.Deallocate()
+		ui.texture = nil
+	}
+	if ui.backTexture != nil {
+		ui.backTexture.Deallocate()
+		ui.backTexture = nil
+	}
+	ui.forceCopy = true
+	// Like detectMouseScale, without its log: a window being dragged to a
+	// new size resizes the view every frame.
+	if sw, _ := ui.SurfaceSize(); sw > 0 {
+		ui.mouseScale = float64(sw) / float64(width)
+	}
+	return nil
+}
+
+// FitToScreen resizes the view to the game's logical screen size. Call it
+// from Ebiten's Layout with the size Layout returns, so a resizable window
+// keeps the UI covering the screen; it does nothing while the size is
+// unchanged, so calling it every Layout is cheap:
+//
+//	func (g *Game) Layout(w, h int) (int, int) {
+//		g.ui.FitToScreen(w, h)
+//		return w, h
+//	}
+//
+// A size that failed (e.g. with an old bridge) is not retried until the
+// layout size changes again.
+func (ui *UltralightUI) FitToScreen(layoutW, layoutH int) error {
+	if layoutW == ui.fitW && layoutH == ui.fitH {
+		return nil
+	}
+	ui.fitW, ui.fitH = layoutW, layoutH
+	return ui.SetSize(layoutW, layoutH)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ResourceRequest describes a request the page is about to make, as passed
+// to OnResourceRequest.
+type ResourceRequest struct {
+	URL    string // absolute URL, resolved against the page
+	Method string // HTTP method, upper case ("GET", "POST", ...)
+	Type   string // "fetch" or "xhr"
+}
+
+// injectResourceHelper installs the script behind OnResourceRequest.
+// Ultralight's C API has no resource request listener, so fetch() and
+// asynchronous XMLHttpRequest are wrapped in JS: each request is held until
+// Go answers its __resource message with the URL to use or a block. Fetches
+// that are blocked reject with a TypeError, like a network error; blocked
+// XHRs are aborted. Synchronous XHR cannot wait for Go and passes through.
+func (ui *UltralightUI) injectResourceHelper() {
+	ui.Eval(`(function(){
+if(window.__ulResInit)return;window.__ulResInit=1;
+var pend={},seq=0;
+window.__ulResReply=function(id,u,b){var f=pend[id];delete pend[id];if(f)f(u,b);};
+function abs(u){try{return new URL(u,location.href).href;}catch(e){return String(u);}}
+function ask(u,m,t,cb){
+if(!window.go||!window.go.send){cb(u,false);return;}
+var id=++seq;pend[id]=cb;window.go.send({action:'__resource',id:id,url:u,method:m,type:t});
+}
+var F=window.fetch;
+if(F)window.fetch=function(input,init){
+var req=(typeof Request!=='undefined'&&input instanceof Request)?input:null;
+var u=abs(req?req.url:input),m=String((init&&init.method)||(req&&req.method)||'GET').toUpperCase();
+return new Promise(function(res,rej){ask(u,m,'fetch',function(nu,b){
+if(b){rej(new TypeError('Failed to fetch'));return;}
+if(nu!==u)input=req?new Request(nu,req):nu;
+try{res(F.call(window,input,init));}catch(e){rej(e);}
+});});
+};
+var X=window.XMLHttpRequest&&XMLHttpRequest.prototype;if(!X)return;
+var O=X.open,S=X.send,H=X.setRequestHeader;
+X.open=function(m,u,async,user,pass){
+this.__ulReq=async===false?null:{m:String(m).toUpperCase(),u:abs(u),user:user,pass:pass,h:[]};
+return O.apply(this,arguments);
+};
+X.setRequestHeader=function(k,v){if(this.__ulReq)this.__ulReq.h.push([k,v]);return H.call(this,k,v);};
+X.send=function(body){
+var x=this,r=x.__ulReq;if(!r)return S.call(x,body);
+ask(r.u,r.m,'xhr',function(nu,b){
+if(b){x.abort();return;}
+if(nu!==r.u){O.call(x,r.m,nu,true,r.user,r.pass);for(var i=0;i<r.h.length;i++)H.call(x,r.h[i][0],r.h[i][1]);}
+S.call(x,body);
+});
+};
+})();`)
+}
+
+// handleResourceMsg intercepts __resource messages sent by the resource
+// helper, asks OnResourceRequest and replies to the page with the URL to
+// load or a block. Returns true if the message was consumed (caller should
+// skip OnMessage).
+func (ui *UltralightUI) handleResourceMsg(msg string) bool {
+	if !strings.HasPrefix(msg, "{\"action\":\"__resource\"") {
+		return false
+	}
+	var data struct {
+		Action string `json:"action"`
+		ID     int    `json:"id"`
+		URL    string `json:"url"`
+		Method string `json:"method"`
+		Type   string `json:"type"`
+	}
+	if json.Unmarshal([]byte(msg), &data) != nil || data.Action != "__resource" {
+		return false
+	}
+	url, block := data.URL, false
+	if ui.OnResourceRequest != nil {
+		rewritten, b := ui.OnResourceRequest(ResourceRequest{URL: data.URL, Method: data.Method, Type: data.Type})
+		block = b
+		if rewritten != "" {
+			url = rewritten
+		}
+	}
+	u, _ := json.Marshal(url)
+	ui.Eval("window.__ulResReply&&__ulResReply(" + strconv.Itoa(data.ID) + "," + string(u) + "," + strconv.FormatBool(block) + ")")
+	return true
+}
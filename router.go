@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "encoding/json"
+
+// MessageRouter dispatches JSON messages to handlers by the value of a
+// top-level type field, replacing a growing switch in OnMessage:
+//
+//	router := ultralightui.NewRouter()
+//	router.Handle("buy", func(raw json.RawMessage) { ... })
+//	ui.OnMessage = router.Dispatch
+//
+// Register handlers before messages are dispatched; a router is not safe
+// for concurrent registration and dispatch.
+type MessageRouter struct {
+	key      string
+	handlers map[string]func(raw json.RawMessage)
+	fallback func(msg string)
+}
+
+// NewRouter returns a router that reads the "type" field.
+func NewRouter() *MessageRouter {
+	return &MessageRouter{key: "type", handlers: make(map[string]func(json.RawMessage))}
+}
+
+// SetTypeKey changes the field the router dispatches on, e.g. "action" for
+// messages like {action:"move"}. It returns r for chaining.
+func (r *MessageRouter) SetTypeKey(key string) *MessageRouter {
+	r.key = key
+	return r
+}
+
+// Handle registers fn for messages whose type field equals typ, replacing
+// any previous handler. fn receives the whole message, ready for
+// json.Unmarshal into a struct.
+func (r *MessageRouter) Handle(typ string, fn func(raw json.RawMessage)) {
+	r.handlers[typ] = fn
+}
+
+// Fallback sets the handler for messages that match no registered type:
+// plain strings, JSON without a string type field, and unknown types.
+// Without one, such messages are dropped.
+func (r *MessageRouter) Fallback(fn func(msg string)) {
+	r.fallback = fn
+}
+
+// Dispatch routes msg to its handler. Its signature matches OnMessage.
+func (r *MessageRouter) Dispatch(msg string) {
+	var fields map[string]json.RawMessage
+	var typ string
+	if json.Unmarshal([]byte(msg), &fields) == nil && json.Unmarshal(fields[r.key], &typ) == nil {
+		if fn := r.handlers[typ]; fn != nil {
+			fn(json.RawMessage(msg))
+			return
+		}
+	}
+	if r.fallback != nil {
+		r.fallback(msg)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "math"
+
+// defaultScrollFriction is the share of the remaining smooth-scroll distance
+// kept after each 60 Hz frame when Options.ScrollFriction is 0.
+const defaultScrollFriction = 0.8
+
+// wheelPixels is how many pixels one wheel notch scrolls.
+const wheelPixels = 100
+
+// smoothScroller spreads wheel deltas over several frames with an
+// exponential ease-out (Options.SmoothScroll).
+type smoothScroller struct {
+	pending float64 // pixels still to scroll; positive scrolls up
+}
+
+// add queues a wheel delta in pixels. Reversing direction drops what was
+// left of the previous scroll, so the page doesn't drift the wrong way.
+func (s *smoothScroller) add(dy float64) {
+	if dy*s.pending < 0 {
+		s.pending = 0
+	}
+	s.pending += dy
+}
+
+// step returns the whole pixels to scroll this tick. keep is the share of the
+// remaining distance kept for later ticks; the last pixels go one at a time,
+// and a sub-pixel remainder is dropped.
+func (s *smoothScroller) step(keep float64) int32 {
+	if s.pending == 0 {
+		return 0
+	}
+	move := s.pending * (1 - keep)
+	if math.Abs(move) < 1 {
+		move = math.Copysign(1, s.pending)
+	}
+	n := int32(move)
+	if math.Abs(float64(n)) > math.Abs(s.pending) {
+		n = 0
+	}
+	if n == 0 {
+		s.pending = 0
+		return 0
+	}
+	s.pending -= float64(n)
+	return n
+}
+
+// scrollKeep converts a per-60 Hz-frame friction to the share kept per tick
+// at tps, so the scroll takes the same time at any tick rate.
+func scrollKeep(friction, tps float64) float64 {
+	if friction <= 0 || friction >= 1 {
+		friction = defaultScrollFriction
+	}
+	return math.Pow(friction, 60/tps)
+}
+
+// forwardScroll sends wheel movement to the view: at once, or spread over
+// the next ticks with Options.SmoothScroll. Smooth scrolls already started
+// keep going when the cursor leaves the view.
+func (ui *UltralightUI) forwardScroll(inBounds bool, wheelY float64) {
+	if !ui.smoothScroll {
+		if inBounds && wheelY != 0 {
+			ulViewFireScroll(ui.viewID, scrollEventTypeByPixel, 0, int32(wheelY*wheelPixels))
+		}
+		return
+	}
+	if inBounds && wheelY != 0 {
+		ui.scroller.add(wheelY * wheelPixels)
+	}
+	if dy := ui.scroller.step(scrollKeep(ui.scrollFriction, tickRate())); dy != 0 {
+		ulViewFireScroll(ui.viewID, scrollEventTypeByPixel, 0, dy)
+	}
+}
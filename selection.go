@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// GetSelectionText returns the text currently selected in the view, e.g. for
+// a "copy quote" action. A selection inside a focused <input> or <textarea>
+// is read from the field, since the page selection doesn't include it.
+// The view must be ready (see EvalSync).
+func (ui *UltralightUI) GetSelectionText() (string, error) {
+	return ui.EvalSync(`(function(){var e=document.activeElement,t=e&&e.tagName;
+if((t==='INPUT'||t==='TEXTAREA')&&typeof e.selectionStart==='number')return e.value.substring(e.selectionStart,e.selectionEnd);
+var s=window.getSelection();return s?s.toString():''})()`)
+}
+
+// SelectRange selects the characters from start to end (exclusive) of the
+// first element matching selector. Inputs and textareas are focused and get
+// a field selection; other elements, contenteditable included, get a page
+// selection over their text, counted across child elements. Offsets are
+// clamped to the text length; a missing element is ignored.
+func (ui *UltralightUI) SelectRange(selector string, start, end int) {
+	sel, _ := json.Marshal(selector)
+	ui.Eval(`(function(q,a,b){var e=document.querySelector(q);if(!e)return;
+if(b<a){var x=a;a=b;b=x}
+var t=e.tagName;
+if((t==='INPUT'||t==='TEXTAREA')&&typeof e.selectionStart==='number'){
+var n=e.value.length;e.focus();try{e.setSelectionRange(Math.min(a,n),Math.min(b,n))}catch(_){}return}
+if(e.isContentEditable&&e.focus)e.focus();
+var w=document.createTreeWalker(e,NodeFilter.SHOW_TEXT,null,false),pos=0,node,r=document.createRange(),sa=0,last=null;
+r.setStart(e,0);r.setEnd(e,0);
+while((node=w.nextNode())){var l=node.nodeValue.length;last=node;
+if(!sa&&a<=pos+l){r.setStart(node,a-pos);sa=1}
+if(sa&&b<=pos+l){r.setEnd(node,b-pos);sa=2;break}
+pos+=l}
+if(sa===1&&last)r.setEnd(last,last.nodeValue.length);
+if(!sa&&last){r.setStart(last,last.nodeValue.length);r.setEnd(last,last.nodeValue.length)}
+var s=window.getSelection();if(!s)return;s.removeAllRanges();s.addRange(r);
+})(` + string(sel) + `,` + strconv.Itoa(max(start, 0)) + `,` + strconv.Itoa(max(end, 0)) + `)`)
+}
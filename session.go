@@ -0,0 +1,165 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSessionClosed is returned when creating a view in a closed Session.
+var ErrSessionClosed = errors.New("ultralightui: session is closed")
+
+// createErrSessionClosed is the createView code for a closed Options.Session.
+// It never comes from the bridge.
+const createErrSessionClosed = -20
+
+// Session is a group of views with their own cookies, local storage and
+// cache, isolated from the views of other sessions and of the default session
+// (views created without Options.Session). Use one per trust level, e.g. the
+// game UI in the default session and mod-provided pages in another:
+//
+//	mods, err := ultralightui.NewSession("mods", false, nil)
+//	modView, err := ultralightui.NewFromURL(800, 600, url, &ultralightui.Options{Session: mods})
+//	...
+//	mods.Close() // closes modView; the game UI is untouched
+//
+// All sessions share the one Ultralight renderer, which lives for the whole
+// program: closing a session releases its views and storage only.
+type Session struct {
+	id   int32
+	name string
+
+	mu        sync.Mutex
+	views     map[*UltralightUI]struct{}
+	pending   int // views being created in this session, not yet added
+	closed    bool
+	destroyed bool
+}
+
+// NewSession creates a session named name. A persistent session keeps its
+// cookies and local storage on disk (under name in the cache directory)
+// across runs; otherwise they live in memory until Close. opts is only used
+// to load the bridge and initialize Ultralight, as in Preload. The bridge
+// holds up to 7 sessions besides the default one.
+func NewSession(name string, persistent bool, opts *Options) (*Session, error) {
+	if err := Preload(opts); err != nil {
+		return nil, err
+	}
+	if ulCreateSession == nil {
+		return nil, errUnsupported("ul_create_session")
+	}
+	p := int32(0)
+	if persistent {
+		p = 1
+	}
+	switch id := ulCreateSession(name, p); {
+	case id == -3:
+		return nil, errUnsupported("ulCreateSession")
+	case id < 0:
+		return nil, fmt.Errorf("ul_create_session failed with code %d", id)
+	default:
+		return &Session{id: id, name: name, views: map[*UltralightUI]struct{}{}}, nil
+	}
+}
+
+// Name returns the name the session was created with.
+func (s *Session) Name() string {
+	return s.name
+}
+
+// ViewCount returns the number of open views in the session.
+func (s *Session) ViewCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.views)
+}
+
+// Close closes every view of the session (see UltralightUI.Close) and then
+// destroys the session's storage. Views in an Update finish it first: the
+// session is destroyed once its last view is. Creating a view in a closed
+// session fails with ErrSessionClosed. Close is idempotent.
+func (s *Session) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	views := make([]*UltralightUI, 0, len(s.views))
+	for ui := range s.views {
+		views = append(views, ui)
+	}
+	s.mu.Unlock()
+
+	for _, ui := range views {
+		ui.Close()
+	}
+	s.mu.Lock()
+	s.destroyIfEmpty()
+	s.mu.Unlock()
+}
+
+// bridgeID returns the bridge session id; nil is the default session (0).
+func (s *Session) bridgeID() int32 {
+	if s == nil {
+		return 0
+	}
+	return s.id
+}
+
+// reserve counts a view about to be created in the session. Returns false if
+// the session is closed.
+func (s *Session) reserve() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.pending++
+	return true
+}
+
+// unreserve undoes reserve after a failed creation.
+func (s *Session) unreserve() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending--
+	s.destroyIfEmpty()
+}
+
+// add turns a reservation into a view of the session. Returns false if the
+// session was closed meanwhile; the caller then closes the view.
+func (s *Session) add(ui *UltralightUI) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending--
+	if s.closed {
+		return false
+	}
+	s.views[ui] = struct{}{}
+	return true
+}
+
+// release removes a destroyed view and destroys the closed session once its
+// last view is gone.
+func (s *Session) release(ui *UltralightUI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.views, ui)
+	s.destroyIfEmpty()
+}
+
+// destroyIfEmpty destroys the bridge session once it is closed and has no
+// views left. s.mu must be held.
+func (s *Session) destroyIfEmpty() {
+	if !s.closed || s.destroyed || len(s.views) > 0 || s.pending > 0 {
+		return
+	}
+	s.destroyed = true
+	if ulDestroySession != nil {
+		ulDestroySession(s.id)
+	}
+}
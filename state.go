@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// stateVersion is the version written by MarshalState. RestoreState accepts
+// blobs up to this version.
+const stateVersion = 1
+
+// viewState is the blob produced by MarshalState.
+type viewState struct {
+	Version int         `json:"version"`
+	URL     string      `json:"url"`
+	ScrollX float64     `json:"scrollX"`
+	ScrollY float64     `json:"scrollY"`
+	Zoom    float64     `json:"zoom"`
+	Fields  []formField `json:"fields,omitempty"`
+}
+
+// formField is the saved value of one form control, identified by stateKeyJS.
+type formField struct {
+	Key      string `json:"k"`
+	Value    string `json:"v,omitempty"`
+	Checked  bool   `json:"c,omitempty"`
+	Selected []bool `json:"s,omitempty"` // <select multiple>
+}
+
+// stateKeyJS identifies a form control the same way when saving and restoring:
+// by id, else by name and position among controls with that name, else by
+// position among all controls. Password and file inputs are never saved.
+const stateKeyJS = `function ctl(){var all=document.querySelectorAll('input,textarea,select'),out=[],names={};
+for(var i=0;i<all.length;i++){var e=all[i],t=(e.type||'').toLowerCase();
+if(t==='password'||t==='file'||t==='submit'||t==='button'||t==='reset'||t==='image')continue;
+var k;if(e.id)k='#'+e.id;else if(e.name){names[e.name]=(names[e.name]||0)+1;k='@'+e.name+':'+names[e.name]}else k=':'+i;
+out.push({k:k,e:e,t:t})}return out}`
+
+// MarshalState captures the page state a game save needs to bring the view
+// back: the current URL, scroll position, form control values and zoom, as a
+// versioned JSON blob for RestoreState. Password and file inputs are skipped.
+// The view must be ready (see EvalSync).
+func (ui *UltralightUI) MarshalState() ([]byte, error) {
+	res, err := ui.EvalSync(`(function(){` + stateKeyJS + `
+var f=[],c=ctl();
+for(var i=0;i<c.length;i++){var x=c[i],e=x.e,r={k:x.k};
+if(x.t==='checkbox'||x.t==='radio')r.c=e.checked;
+else if(x.t==='select-multiple'){r.s=[];for(var j=0;j<e.options.length;j++)r.s.push(e.options[j].selected)}
+else r.v=e.value;
+f.push(r)}
+return JSON.stringify({url:location.href,scrollX:window.scrollX||0,scrollY:window.scrollY||0,fields:f})})()`)
+	if err != nil {
+		return nil, err
+	}
+	var st viewState
+	if err := json.Unmarshal([]byte(res), &st); err != nil {
+		return nil, fmt.Errorf("ultralightui: reading page state: %w", err)
+	}
+	st.Version = stateVersion
+	st.Zoom = ui.GetZoom()
+	return json.Marshal(st)
+}
+
+// RestoreState applies a blob from MarshalState: zoom, form values (firing
+// input and change events so page scripts see them) and scroll position.
+// The view must show the same document the state was saved from; only the
+// URL fragment may differ, and is restored. Load the saved page first (its
+// URL is in the blob) if it may have changed.
+func (ui *UltralightUI) RestoreState(data []byte) error {
+	var st viewState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("ultralightui: invalid state: %w", err)
+	}
+	if st.Version < 1 || st.Version > stateVersion {
+		return fmt.Errorf("ultralightui: unsupported state version %d", st.Version)
+	}
+	cur, err := ui.EvalSync("location.href")
+	if err != nil {
+		return err
+	}
+	if stripFragment(cur) != stripFragment(st.URL) {
+		return fmt.Errorf("ultralightui: state is for %s, view shows %s", st.URL, cur)
+	}
+	if st.Zoom != 0 {
+		ui.SetZoom(st.Zoom)
+	}
+	fields, err := json.Marshal(st.Fields)
+	if err != nil {
+		return err
+	}
+	pos, _ := json.Marshal([]float64{st.ScrollX, st.ScrollY})
+	hash, _ := json.Marshal(st.URL[len(stripFragment(st.URL)):])
+	ui.Eval(`(function(){` + stateKeyJS + `
+var f=` + string(fields) + `,by={},c=ctl(),p=` + string(pos) + `,h=` + string(hash) + `;
+for(var i=0;i<f.length;i++)by[f[i].k]=f[i];
+for(var i=0;i<c.length;i++){var x=c[i],e=x.e,r=by[x.k];if(!r)continue;
+if(x.t==='checkbox'||x.t==='radio')e.checked=!!r.c;
+else if(x.t==='select-multiple'){for(var j=0;j<e.options.length;j++)e.options[j].selected=!!(r.s&&r.s[j])}
+else e.value=r.v||'';
+e.dispatchEvent(new Event('input',{bubbles:true}));e.dispatchEvent(new Event('change',{bubbles:true}))}
+if(h!==location.hash&&(h||location.hash))location.hash=h;
+window.scrollTo(p[0],p[1])})()`)
+	return nil
+}
+
+// stripFragment returns url without its #fragment.
+func stripFragment(url string) string {
+	if i := strings.IndexByte(url, '#'); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import "image"
+
+// RenderStats reports what the view did during the most recent Update.
+// Use it to check whether a page keeps repainting when it should be idle
+// (e.g. a CSS animation forcing a copy every frame).
+type RenderStats struct {
+	// RenderedThisFrame is true if Ultralight produced new pixels and they were
+	// copied to the texture during the last Update.
+	RenderedThisFrame bool
+	// Frames is the number of Update/UpdateNoTick calls so far.
+	Frames int
+	// PixelCopyCount is the total number of frames that copied pixels.
+	PixelCopyCount int
+	// LastCopyBytes is the size in bytes of the last pixel copy: the RGBA
+	// pixels of LastDirtyRect, or of the whole surface for full copies (first
+	// frame, after being hidden, Options.DoubleBuffer, older bridges).
+	LastCopyBytes int
+	// LastDirtyRect is the area Ultralight reported as changed for the last
+	// copy. A rect covering the whole view on every frame means full repaints.
+	LastDirtyRect image.Rectangle
+}
+
+// Stats returns the render statistics of the view.
+func (ui *UltralightUI) Stats() RenderStats {
+	s := ui.stats
+	s.Frames = ui.frameCount
+	return s
+}
+
+// recordPixelCopy updates the stats after a successful pixel copy.
+func (ui *UltralightUI) recordPixelCopy(n int) {
+	ui.stats.RenderedThisFrame = true
+	ui.stats.PixelCopyCount++
+	ui.stats.LastCopyBytes = n
+	ui.stats.LastDirtyRect = lastDirtyRect(ui.viewID)
+}
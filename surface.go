@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// LockPixels locks the view's Ultralight surface and returns its pixel
+// memory, for consumers that sample the surface directly (e.g. a GPU
+// compositor uploading it as a texture) instead of the RGBA copy in Pixels.
+//
+// The memory is BGRA, premultiplied, in rows of stride bytes; stride may be
+// larger than 4*width (row padding). Its size is SurfaceSize, which on HiDPI
+// displays may exceed the view's size. ptr is nil if the view is closed, has
+// no surface yet, or is already locked; unlock is never nil.
+//
+// The memory belongs to the bridge and is only valid until unlock. Call
+// unlock before the next Tick, Update, UpdateNoTick or Close on any view: the
+// renderer paints surfaces during ticks and Close frees them. Do not keep ptr
+// or slices over it after unlock, and use it from the goroutine that runs
+// Update. Close unlocks a surface left locked. After unlock, the next Update
+// copies the whole frame, since unlocking resets the surface's dirty area.
+func (ui *UltralightUI) LockPixels() (ptr unsafe.Pointer, stride int, unlock func()) {
+	unlock = func() {}
+	if ui.closed.Load() || ui.unlockPixels != nil {
+		return nil, 0, unlock
+	}
+	p := ulViewGetPixels(ui.viewID)
+	if p == nil {
+		return nil, 0, unlock
+	}
+	// Each lock has its own unlock: a stale or repeated call from an earlier
+	// lock must not release a newer one.
+	var once sync.Once
+	unlock = func() {
+		once.Do(func() {
+			ui.unlockPixels = nil
+			ulViewUnlockPixels(ui.viewID)
+			ui.forceCopy = true
+		})
+	}
+	ui.unlockPixels = unlock
+	return p, int(ulViewGetRowBytes(ui.viewID)), unlock
+}
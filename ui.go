@@ -4,15 +4,22 @@
 package ultralightui
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	htmlpkg "html"
+	"io/fs"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -67,47 +74,228 @@ func HasInputFocus() bool {
 	return ifvid >= 0 && getFocusedViewID() == ifvid
 }
 
+// HasInputFocus reports whether a text input element is focused in this view's
+// DOM, whether or not the view has keyboard focus. Unlike the package-level
+// HasInputFocus, several views can report true at once (e.g. a chat box and a
+// search box in different views). Always false after Close.
+func (ui *UltralightUI) HasInputFocus() bool {
+	return !ui.closed.Load() && ui.inputFocused.Load()
+}
+
+// BlurInput removes DOM focus from the page's active element, e.g. when the
+// game hides a form panel, so keybindings aren't left suppressed by an input
+// nobody can see. HasInputFocus reports false right away, and
+// OnInputFocusChange is called with false if an input was focused. Unlike
+// ClearFocus, the view keeps keyboard focus.
+func (ui *UltralightUI) BlurInput() {
+	if ui.closed.Load() {
+		return
+	}
+	if ui.domReady {
+		ui.Eval("(function(){var e=document.activeElement;if(e&&e!==document.body&&e.blur)e.blur()})()")
+	}
+	inputFocusViewID.CompareAndSwap(ui.viewID, -1)
+	if ui.inputFocused.Swap(false) && ui.OnInputFocusChange != nil {
+		ui.OnInputFocusChange(false)
+	}
+}
+
 func getFocusedViewID() int32 {
 	return focusedViewID.Load()
 }
 
 func setFocusedViewID(viewID int32) {
-	focusedViewID.Store(viewID)
+	old := focusedViewID.Swap(viewID)
+	if old == viewID || old < 0 {
+		return
+	}
+	if prev, ok := ViewByID(old); ok && prev.blurOnFocusLoss {
+		prev.BlurInput()
+	}
 }
 
 // Options for creating the UI. All fields are optional.
 type Options struct {
 	BaseDir string // Directory containing the bridge shared library and Ultralight SDK libraries. Defaults to working directory.
-	Debug   bool   // Enable debug logging (creates bridge.log and ultralight.log). Default false.
+
+	// BridgePath is the full path of the bridge shared library, e.g. a
+	// versioned folder chosen at runtime. When set, exactly that file is
+	// loaded instead of searching BaseDir, the working directory and the
+	// executable's directory, and BaseDir defaults to its directory.
+	BridgePath string
+	Debug      bool // Enable debug logging (creates bridge.log and ultralight.log). Default false.
+
+	// Logger receives the view's JS console messages (console.log, errors,
+	// warnings) at the matching level and its failed loads, tagged with the
+	// view ID. When set, Debug no longer writes bridge.log and ultralight.log:
+	// those come from the bridge and SDK internals and are not routed here.
+	// Without a Logger, console messages are discarded.
+	Logger *slog.Logger
+
+	// DisableImages skips image loading and decoding in the view (text-only
+	// rendering). Useful as a lightweight profile on low-end hardware.
+	// Requires an Ultralight SDK exporting ulViewConfigSetEnableImages; on older
+	// SDKs the flag is ignored and logged to bridge.log.
+	DisableImages bool
+
+	// DisableJavaScript renders the page as static HTML and CSS, without
+	// running its scripts, e.g. for untrusted community themes. Messaging is
+	// unavailable in both directions: Eval, Send and the other script calls
+	// are no-ops, EvalSync (and QuerySelector, PreferredSize, ...) returns
+	// ErrJavaScriptDisabled, and the page can't call go.send. The built-in
+	// helpers (undo, input focus tracking) are not installed either, and
+	// AnimationPending always reports false (CSS animations still run and
+	// show up in NeedsRedraw). Requires
+	// an Ultralight SDK exporting ulViewConfigSetEnableJavaScript; otherwise
+	// creation fails with ErrUnsupported rather than running the scripts.
+	DisableJavaScript bool
+
+	// Focused gives the view keyboard focus on creation, like calling SetFocus.
+	// Only one view can be focused: if several are created with Focused, the
+	// last one created wins.
+	Focused bool
+
+	// DoubleBuffer renders each frame into a back texture and swaps it in once
+	// complete, so GetTexture always returns a whole frame, and a texture
+	// obtained from it stays intact until the following frame is presented.
+	// The Pixels buffer is double-buffered the same way. Costs one extra
+	// texture and pixel buffer of the view's size.
+	DoubleBuffer bool
+
+	// StrictMessaging reports go.send messages that arrive while the view has
+	// no OnMessage and there is no SetDefaultMessageHandler, instead of dropping
+	// them silently: the first one is logged, or every one panics with Debug.
+	StrictMessaging bool
+
+	// BaseURL is the URL relative resources resolve against in pages loaded
+	// from HTML bytes or a file (NewFromHTML, NewFromFile), e.g. "file:///ui/"
+	// for a directory served from disk or registered in the VFS. It is applied
+	// by inserting <base href> at the top of <head> (taking precedence over a
+	// <base> in the page). Ignored by NewFromURL and NewFromFS.
+	BaseURL string
+
+	// VFSFilter chooses which files NewFromFS, NewFromFSAsync and
+	// NewFromHTTPFS register in the VFS: it gets each file's normalized VFS
+	// path (e.g. "ui/app.js.map") and returns false to skip it, e.g. to keep
+	// sourcemaps and docs out of memory. nil registers every file.
+	VFSFilter func(path string) bool
+
+	// KeyRepeatDelayMs and KeyRepeatIntervalMs set how long a non-character key
+	// (Backspace, Delete, arrows, Home/End...) must be held before it repeats,
+	// and the time between repeats. They are measured in time, not ticks, so
+	// repeat speed doesn't depend on the TPS. Defaults: 500 and 33 ms.
+	// Character keys repeat through the OS text input and are unaffected.
+	KeyRepeatDelayMs    int
+	KeyRepeatIntervalMs int
+
+	// CharFilter decides which typed runes are sent to the page as character
+	// input. nil keeps the default: everything except control characters and
+	// DEL. Accepting '\t' makes Tab type a tab (e.g. in a code editor
+	// textarea): the OS text input doesn't report Tab, so the view sends the
+	// '\t' with each Tab key press and repeat. Tab still moves focus unless the
+	// page calls preventDefault on its keydown.
+	CharFilter func(r rune) bool
+
+	// DeviceScale is the page's window.devicePixelRatio (default 1), so
+	// resolution media queries and srcset pick high-DPI assets. The view size
+	// stays in pixels: create it at width*scale by height*scale for the CSS
+	// layout to keep its size, e.g. with ebiten.Monitor().DeviceScaleFactor().
+	// Needs a bridge with ul_set_view_device_scale; see also SetDeviceScale.
+	DeviceScale float64
+
+	// UserAgent replaces Ultralight's default user agent for this view, both
+	// navigator.userAgent and the User-Agent header of its requests, e.g. to
+	// get a mobile layout or identify the game to a web service. It is
+	// per-view and fixed at creation: Ultralight has no way to change it on a
+	// live view. Empty keeps the default.
+	UserAgent string
+
+	// Renderer selects the Ultralight renderer. The zero value, RendererAuto,
+	// and RendererCPU use the CPU renderer, which needs no GPU access and so
+	// also works over RDP, in VMs and on headless CI. RendererGPU is rejected
+	// with ErrUnsupported: the bridge has no GPU driver.
+	Renderer Renderer
+
+	// DefaultFontFamily is the font used by text with no font-family (the
+	// standard family), e.g. a font bundled with RegisterFont so the UI looks
+	// the same on machines lacking the page's fonts. Like UserAgent it is
+	// per-view and fixed at creation; no init-time setting is involved. Empty
+	// keeps Ultralight's default. Generic families (sans-serif, serif,
+	// monospace) are unaffected.
+	DefaultFontFamily string
+
+	// Session puts the view in a Session, with its own cookies and storage.
+	// nil uses the default session shared by all other views.
+	Session *Session
+
+	// WarmupScript is run once when the DOM is ready, after the built-in
+	// helpers and before OnReady, e.g. to exercise hot JS paths so the first
+	// user interaction doesn't pay the JIT warmup cost.
+	WarmupScript string
+
+	// DisableEditHelpers skips the built-in undo/redo and select-all for text
+	// fields, for pages with their own editing history: Ctrl+Z/Y/A (Cmd on
+	// macOS) then reach the page as normal key events. go.send is unaffected.
+	DisableEditHelpers bool
+
+	// BlurOnFocusLoss blurs the page's focused text field whenever the view
+	// loses keyboard focus (SetFocus on another view, ClearFocus, a click
+	// elsewhere), as BlurInput does. The view's HasInputFocus then stays in
+	// step with where the user types when switching between views.
+	BlurOnFocusLoss bool
+
+	// SmoothScroll spreads each mouse wheel step over several frames with an
+	// ease-out, instead of jumping the whole distance at once. ScrollFriction
+	// (0 to 1, default 0.8) is the share of the remaining distance kept after
+	// each 1/60 s: higher values glide longer.
+	SmoothScroll   bool
+	ScrollFriction float64
 }
 
 // UltralightUI represents an HTML view rendered as an Ebiten texture.
 // Multiple instances can exist; each has its own view in the Ultralight bridge.
 type UltralightUI struct {
-	viewID  int32
-	texture *ebiten.Image
-	pixels  []byte
+	view // bridge view and pixel frames (view.go)
 
-	width  int
-	height int
+	// Created on the first GetTexture, so views used only through Pixels or
+	// headless rendering never need a graphics context.
+	texture      *ebiten.Image
+	backTexture  *ebiten.Image // Options.DoubleBuffer: frame being written
+	doubleBuffer bool
+	dirtyBuf     []byte // dirty rect of the last copy, packed for WritePixels
+	drawBuf      []byte // clipped frame packed for DrawTo
 
 	// Bounds in screen coordinates for input routing. Set via SetBounds so that
 	// only the view under the cursor receives mouse/scroll input.
 	BoundsX, BoundsY, BoundsW, BoundsH int
 
-	mouseX, mouseY int
-	mouseInside    bool // true if cursor is inside bounds (to detect leave)
-	leftDown       bool
-	rightDown      bool
-	leftOutside    bool // left button was pressed outside bounds (ignore on re-enter)
-	rightOutside   bool // right button was pressed outside bounds
-	domReady       bool
-	frameCount     int
-	goHelperInjected bool
+	mouseX, mouseY           int
+	mouseInside              bool // true if cursor is inside bounds (to detect leave)
+	leftDown                 bool
+	rightDown                bool
+	middleDown               bool
+	extraDown                [2]bool      // back/forward buttons, reported via OnMouseButton
+	clicks                   clickCounter // double/triple click detection (click.go)
+	leftOutside              bool         // left button was pressed outside bounds (ignore on re-enter)
+	rightOutside             bool         // right button was pressed outside bounds
+	middleOutside            bool         // middle button was pressed outside bounds
+	domReady                 bool
+	frameCount               int
+	goHelperInjected         bool
+	downloadHelperInjected   bool
+	overflowHelperInjected   bool
+	navigationHelperInjected bool
+	resourceHelperInjected   bool
+	readyFired               bool
 
 	// Reusable buffers to avoid per-frame allocations in forwardKeyboard
-	keyBuf     []ebiten.Key
-	charBuf    []rune
+	keyBuf   []ebiten.Key
+	charBuf  []rune
+	touchBuf []ebiten.TouchID
+
+	pinch pinchTracker // two-finger pinch (pinch.go)
+
+	loads loadCounters // load events already reported (load.go)
 
 	// mouseScale is the ratio of actual surface size to requested size.
 	// Used to scale mouse coordinates for HiDPI (e.g., macOS Retina where
@@ -118,19 +306,181 @@ type UltralightUI struct {
 	// msg is a string or JSON string. Use ParseMessage to get structured data.
 	OnMessage func(msg string)
 
+	// OnDownload is called when the page downloads a file (<a download>,
+	// including blob: and data: URLs). filename is the name suggested by the
+	// page, reduced to its base name; the host decides whether and where to save.
+	OnDownload func(filename string, data []byte)
+
+	// OnFileDrop is called when files are dropped from the OS onto the view.
+	// Ebitengine exposes dropped files as a virtual file system, not OS paths:
+	// names are the dropped entries at the root of files. x, y are view-local
+	// coordinates, computed like the mouse events sent to the page.
+	OnFileDrop func(files fs.FS, names []string, x, y int)
+
+	// OnOverflow is called when the page's content starts or stops exceeding
+	// the view in each axis (scroll size larger than client size), e.g. to draw
+	// "more below" indicators. It is first called with the initial state.
+	OnOverflow func(horizontal, vertical bool)
+
+	// OnNavigationRequest is called when the user clicks a link that would
+	// navigate the view to url. Return false to cancel, e.g. to open external
+	// links in the system browser instead. Only link clicks are reported:
+	// navigations from scripts (location, window.open) and forms are not.
+	OnNavigationRequest func(url string) (allow bool)
+
+	// OnResourceRequest is called before the page fetches a resource with
+	// fetch() or XMLHttpRequest, e.g. to redirect CDN URLs to VFS copies or
+	// block trackers. Return a non-empty rewrittenURL to load that instead,
+	// or block to fail the request. Resources loaded by the document itself
+	// (<img>, <script>, <link>) and requests made before DOM ready are not
+	// reported: the C API has no resource request listener.
+	OnResourceRequest func(req ResourceRequest) (rewrittenURL string, block bool)
+
+	// OnInputFocusChange is called when a text input element (input, textarea,
+	// contenteditable) gains or loses focus in this view's DOM, e.g. to suspend
+	// game keybindings or show an on-screen keyboard. If the view holds input
+	// focus when it is closed, it is called with false from Close.
+	OnInputFocusChange func(focused bool)
+
+	// OnMouseButton is called when the back (button 4) or forward (button 5)
+	// mouse button is pressed inside the view's bounds, and when it is released
+	// after such a press. Ultralight has no DOM mapping for them. x, y are
+	// view-local coordinates, computed like the mouse events sent to the page.
+	OnMouseButton func(button int, pressed bool, x, y int)
+
+	// OnPinch is called when a two-finger pinch that started on the view
+	// changes the distance between the fingers. scale is the ratio to the
+	// distance at the previous call (>1 spreading, <1 pinching); cx, cy are the
+	// view-local midpoint. When nil, pinching changes the zoom (SetZoom).
+	OnPinch func(scale float64, cx, cy int)
+
+	// OnReady is called once, from Update, when the DOM is ready and the
+	// built-in helpers and Options.WarmupScript have been queued. Scripts
+	// passed to Eval from it run after the warmup script.
+	OnReady func()
+
+	// OnLoadStart and OnLoadFinish are called from Update when a main-frame
+	// load begins and when it ends, successfully or not, e.g. to show a spinner
+	// while LoadProgress climbs. Both need SDK load callbacks; without them
+	// they are never called and LoadProgress is 1 once the view is ready.
+	OnLoadStart  func()
+	OnLoadFinish func()
+
+	// OnLoadError is called from Update when a main-frame load fails, before
+	// OnLoadFinish. code is the network error code, or LoadErrorStopped for
+	// a load aborted by Stop (url is empty then).
+	OnLoadError func(url, description string, code int)
+
 	// BlockInput, cuando es true, hace que forwardInput trate el cursor como si
 	// estuviese fuera de los bounds. Sirve para evitar que una vista oculta por
 	// otra encima reciba clicks o movimiento. No afecta el teclado si la vista
 	// no tiene foco.
 	BlockInput bool
 
-	closed bool
+	warmupScript string // Options.WarmupScript
+
+	disableEditHelpers bool // Options.DisableEditHelpers
+	jsDisabled         bool // Options.DisableJavaScript: scripts are dropped
+
+	smoothScroll   bool    // Options.SmoothScroll
+	scrollFriction float64 // Options.ScrollFriction; 0 = default
+	scroller       smoothScroller
+
+	keyRepeatDelay, keyRepeatInterval time.Duration     // Options.KeyRepeat*Ms; 0 = default
+	charFilter                        func(r rune) bool // Options.CharFilter
+	deviceScale                       float64           // devicePixelRatio; 0 = 1
+	session                           *Session          // Options.Session; nil = default
+	logger                            *slog.Logger      // Options.Logger (logger.go)
+
+	// Options.StrictMessaging / Options.Debug (see dispatchMessage)
+	strictMessaging bool
+	debug           bool
+	unhandledLogged bool
+
+	inputFocused atomic.Bool // a text input is focused in this view's DOM (__inputFocus)
+
+	unfocusable     bool // SetFocusable(false)
+	blurOnFocusLoss bool // Options.BlurOnFocusLoss
+
+	// Input hit testing (hittest.go): SetHitRect, SetClickThroughAlpha, SetClickThroughEmpty
+	hitX, hitY, hitW, hitH int
+	clickThroughAlpha      uint8
+	clickThroughEmpty      bool
+
+	paused    bool // SetPaused: bridge skips this view, Go skips input and pixel copy
+	wasHidden bool // hidden last Update: the next visible Update forces a pixel copy
+	forceCopy bool // LockPixels reset the dirty area: the next Update forces a pixel copy
+
+	unlockPixels func() // set while LockPixels holds the surface (surface.go)
+
+	fitW, fitH int // last FitToScreen layout size (resize.go)
+
+	inputMode InputMode // SetInputMode (inputmode.go)
+
+	// Quality mode state (see quality.go)
+	quality     QualityMode
+	frameBudget time.Duration
+	frameCost   time.Duration // moving average of Update duration
+	throttled   bool          // QualityAdaptive is currently over budget
+
+	updateInterval time.Duration // SetUpdateInterval; 0 = every Update
+	nextRefresh    time.Time     // when the next interval refresh is due
+	intervalSkip   bool          // this Update is between interval refreshes
+
+	// Send coalescing (SetCoalesceSends): JSON payloads queued until end of update
+	coalesceSends bool
+	pendingSends  [][]byte
+
+	// preReady holds scripts from Eval/Send until the page is ready for them
+	// (scriptsReady), so early calls aren't lost to a page still loading.
+	preReady []string
+
+	lastDiff map[string]json.RawMessage // last state sent by SendDiff
+
+	stats RenderStats // see Stats
+
+	zoom        float64 // SetZoom factor; 0 means 1.0
+	zoomApplied bool
+
+	// SetTextDirection and SetLocale (locale.go)
+	textDir       string
+	locale        string
+	textDirSet    bool // SetTextDirection was called: manage the page's dir
+	localeSet     bool // SetLocale was called: manage the page's lang
+	localeApplied bool
+
+	fontGen int64 // RegisterFont generation in the page's @font-face rules (fonts.go)
+
+	// SetModifierState (modifiers.go)
+	mods        modifierState
+	modsSet     bool
+	modsApplied bool
+
+	// AnimationPending (animation.go)
+	animTracking       bool // AnimationPending was called: inject the helper
+	animHelperInjected bool
+	animKnown          bool // the current page reported its animation state
+	animPending        bool
+
+	// InjectCSS handles; stylesheets injected before the DOM was ready
+	cssSeq     int
+	pendingCSS map[int]string
+
+	// Downloads being reassembled from __download chunks, by page-side id
+	downloads map[int]*pendingDownload
+
+	updating atomic.Bool // an Update/UpdateNoTick is in progress
+
+	name string // SetName; guarded by registryMu
+
+	closed      atomic.Bool
+	destroyOnce sync.Once
 }
 
 // NewFromFile creates a new UI loading HTML from a local file.
 func NewFromFile(width, height int, filePath string, opts *Options) (*UltralightUI, error) {
 	baseDir, debug := resolveOpts(opts)
-	if err := initBridge(baseDir); err != nil {
+	if err := initBridge(bridgePath(opts, baseDir)); err != nil {
 		return nil, fmt.Errorf("bridge: %w", err)
 	}
 	if err := ensureULInit(baseDir, debug); err != nil {
@@ -140,31 +490,49 @@ func NewFromFile(width, height int, filePath string, opts *Options) (*Ultralight
 	if err != nil {
 		return nil, fmt.Errorf("reading HTML file %s: %w", filePath, err)
 	}
-	return newUI(width, height, htmlBytes)
+	return newUI(width, height, htmlBytes, opts)
 }
 
 // NewFromURL creates a new UI loading content from a URL.
 func NewFromURL(width, height int, url string, opts *Options) (*UltralightUI, error) {
 	baseDir, debug := resolveOpts(opts)
-	if err := initBridge(baseDir); err != nil {
+	if err := initBridge(bridgePath(opts, baseDir)); err != nil {
 		return nil, fmt.Errorf("bridge: %w", err)
 	}
 	if err := ensureULInit(baseDir, debug); err != nil {
 		return nil, err
 	}
-	return newUIWithURL(width, height, url)
+	return newUIWithURL(width, height, url, opts)
 }
 
 // NewFromHTML creates a new UI with the given HTML bytes (no file or URL).
 func NewFromHTML(width, height int, html []byte, opts *Options) (*UltralightUI, error) {
 	baseDir, debug := resolveOpts(opts)
-	if err := initBridge(baseDir); err != nil {
+	if err := initBridge(bridgePath(opts, baseDir)); err != nil {
 		return nil, fmt.Errorf("bridge: %w", err)
 	}
 	if err := ensureULInit(baseDir, debug); err != nil {
 		return nil, err
 	}
-	return newUI(width, height, html)
+	return newUI(width, height, html, opts)
+}
+
+// Preload loads the bridge and initializes Ultralight ahead of the first view,
+// so that view's creation doesn't pay the startup cost. Call it from main
+// before ebiten.RunGame, or from a goroutine while a loading screen is shown
+// (the first frame need not wait for it). Only BaseDir, BridgePath, Debug and
+// Renderer are used.
+// Safe to call multiple times: initialization happens once, with the options
+// of the first call (whether that is Preload or a New* constructor).
+func Preload(opts *Options) error {
+	if err := checkRenderer(opts); err != nil {
+		return err
+	}
+	baseDir, debug := resolveOpts(opts)
+	if err := initBridge(bridgePath(opts, baseDir)); err != nil {
+		return fmt.Errorf("bridge: %w", err)
+	}
+	return ensureULInit(baseDir, debug)
 }
 
 // New is a convenience alias for NewFromFile.
@@ -172,56 +540,211 @@ func New(width, height int, htmlPath string, opts *Options) (*UltralightUI, erro
 	return NewFromFile(width, height, htmlPath, opts)
 }
 
-func newUI(width, height int, html []byte) (*UltralightUI, error) {
+func newUI(width, height int, html []byte, opts *Options) (*UltralightUI, error) {
 	if width <= 0 || height <= 0 {
-		return nil, fmt.Errorf("invalid dimensions: %dx%d", width, height)
+		return nil, sizeError(width, height)
+	}
+	if err := checkRenderer(opts); err != nil {
+		return nil, err
+	}
+	if err := checkJavaScript(opts); err != nil {
+		return nil, err
+	}
+	if opts != nil && opts.BaseURL != "" {
+		html = withBaseHref(html, opts.BaseURL)
 	}
 	// Combined create+load in ONE worker roundtrip, no sleeping
-	viewID := ulCreateViewWithHTML(int32(width), int32(height), string(html))
+	viewID := createView(opts, func() int32 {
+		return ulCreateViewWithHTML(int32(width), int32(height), string(html))
+	})
 	if viewID < 0 {
-		return nil, fmt.Errorf("ul_create_view_with_html failed with code %d", viewID)
+		return nil, createError("ul_create_view_with_html", viewID)
 	}
 	registerView()
 
-	ui := &UltralightUI{
-		viewID:  viewID,
-		texture: ebiten.NewImage(width, height),
-		pixels:  make([]byte, width*height*4),
-		width:   width,
-		height:  height,
-	}
+	ui := &UltralightUI{view: newView(viewID, width, height)}
+	ui.registerLive()
 	ui.detectMouseScale()
+	if err := ui.applyOpts(opts); err != nil {
+		return nil, err
+	}
 	return ui, nil
 }
 
-func newUIWithURL(width, height int, url string) (*UltralightUI, error) {
+// withBaseHref returns html with a <base href> element for base inserted right
+// after the opening <head> tag, or after <html>, or else after the doctype (so
+// the page stays in standards mode) or at the start.
+// Ultralight's LoadHTML takes no base URL, so this is how relative URLs resolve.
+func withBaseHref(html []byte, base string) []byte {
+	tag := []byte(`<base href="` + htmlpkg.EscapeString(base) + `">`)
+	lower := bytes.ToLower(html)
+	at := max(openTagEnd(lower, "<!doctype"), 0)
+	for _, name := range []string{"<head", "<html"} {
+		if i := openTagEnd(lower, name); i >= 0 {
+			at = i
+			break
+		}
+	}
+	out := make([]byte, 0, len(html)+len(tag))
+	out = append(out, html[:at]...)
+	out = append(out, tag...)
+	return append(out, html[at:]...)
+}
+
+// openTagEnd returns the index just past the first opening tag called name
+// (e.g. "<head") in lowercased html, or -1.
+func openTagEnd(lower []byte, name string) int {
+	for off := 0; ; {
+		i := bytes.Index(lower[off:], []byte(name))
+		if i < 0 {
+			return -1
+		}
+		i += off + len(name)
+		// Require the name to end here, so "<header" doesn't match "<head".
+		if i < len(lower) && (lower[i] == '>' || lower[i] == ' ' || lower[i] == '\t' || lower[i] == '\n' || lower[i] == '\r') {
+			if j := bytes.IndexByte(lower[i:], '>'); j >= 0 {
+				return i + j + 1
+			}
+			return -1
+		}
+		off = i
+	}
+}
+
+func newUIWithURL(width, height int, url string, opts *Options) (*UltralightUI, error) {
 	if width <= 0 || height <= 0 {
-		return nil, fmt.Errorf("invalid dimensions: %dx%d", width, height)
+		return nil, sizeError(width, height)
+	}
+	if err := checkRenderer(opts); err != nil {
+		return nil, err
+	}
+	if err := checkJavaScript(opts); err != nil {
+		return nil, err
 	}
 	// Combined create+load in ONE worker roundtrip, no sleeping
-	viewID := ulCreateViewWithURL(int32(width), int32(height), url)
+	viewID := createView(opts, func() int32 {
+		return ulCreateViewWithURL(int32(width), int32(height), url)
+	})
 	if viewID < 0 {
-		return nil, fmt.Errorf("ul_create_view_with_url failed with code %d", viewID)
+		return nil, createError("ul_create_view_with_url", viewID)
 	}
 	registerView()
 
-	ui := &UltralightUI{
-		viewID:  viewID,
-		texture: ebiten.NewImage(width, height),
-		pixels:  make([]byte, width*height*4),
-		width:   width,
-		height:  height,
-	}
+	ui := &UltralightUI{view: newView(viewID, width, height)}
+	ui.registerLive()
 	ui.detectMouseScale()
+	if err := ui.applyOpts(opts); err != nil {
+		return nil, err
+	}
 	return ui, nil
 }
 
+// viewFlags converts the per-view fields of opts into bridge VIEW_FLAG_* bits.
+func viewFlags(opts *Options) int32 {
+	if opts == nil {
+		return 0
+	}
+	var flags int32
+	if opts.DisableImages {
+		flags |= viewFlagDisableImages
+	}
+	if opts.DisableJavaScript {
+		flags |= viewFlagDisableJavaScript
+	}
+	return flags
+}
+
+// createMu makes setting the view flags and creating the view one step, so
+// views created from different goroutines don't pick up each other's flags.
+var createMu sync.Mutex
+
+// createView sets the bridge view flags from opts and runs create (one of the
+// ulCreateView* calls), returning its view ID.
+func createView(opts *Options, create func() int32) int32 {
+	createMu.Lock()
+	defer createMu.Unlock()
+	if ulSetViewFlags != nil {
+		ulSetViewFlags(viewFlags(opts))
+	}
+	if ulSetViewDeviceScale != nil {
+		var scale float64
+		if opts != nil {
+			scale = opts.DeviceScale
+		}
+		ulSetViewDeviceScale(scalePermille(scale))
+	}
+	var session *Session
+	var userAgent, fontFamily string
+	if opts != nil {
+		session = opts.Session
+		userAgent = opts.UserAgent
+		fontFamily = opts.DefaultFontFamily
+	}
+	if ulSetViewUserAgent != nil {
+		ulSetViewUserAgent(userAgent)
+	}
+	if ulSetViewFontFamily != nil {
+		ulSetViewFontFamily(fontFamily)
+	}
+	if session != nil && !session.reserve() {
+		return createErrSessionClosed
+	}
+	if ulSetViewSession != nil {
+		ulSetViewSession(session.bridgeID())
+	}
+	viewID := create()
+	if viewID < 0 && session != nil {
+		session.unreserve()
+	}
+	return viewID
+}
+
+// applyOpts applies the Options that act on the Go side of a newly created
+// view. It closes the view and returns ErrSessionClosed if its session was
+// closed while the view was created.
+func (ui *UltralightUI) applyOpts(opts *Options) error {
+	if opts == nil {
+		return nil
+	}
+	if opts.Session != nil {
+		ui.session = opts.Session
+		if !ui.session.add(ui) {
+			ui.Close()
+			return ErrSessionClosed
+		}
+	}
+	if opts.Focused {
+		ui.SetFocus()
+	}
+	ui.strictMessaging = opts.StrictMessaging
+	ui.warmupScript = opts.WarmupScript
+	ui.disableEditHelpers = opts.DisableEditHelpers
+	ui.jsDisabled = opts.DisableJavaScript
+	ui.blurOnFocusLoss = opts.BlurOnFocusLoss
+	ui.smoothScroll = opts.SmoothScroll
+	ui.scrollFriction = opts.ScrollFriction
+	ui.keyRepeatDelay = time.Duration(opts.KeyRepeatDelayMs) * time.Millisecond
+	ui.keyRepeatInterval = time.Duration(opts.KeyRepeatIntervalMs) * time.Millisecond
+	ui.charFilter = opts.CharFilter
+	ui.logger = opts.Logger
+	if ulSetViewDeviceScale != nil { // older bridges create every view at 1x
+		ui.deviceScale = normDeviceScale(opts.DeviceScale)
+	}
+	ui.debug = opts.Debug
+	ui.doubleBuffer = opts.DoubleBuffer
+	ui.setDoubleBuffer(opts.DoubleBuffer)
+	return nil
+}
+
 func resolveOpts(opts *Options) (string, bool) {
 	debug := false
 	baseDir := ""
 	if opts != nil {
 		baseDir = opts.BaseDir
-		debug = opts.Debug
+		debug = opts.Debug && opts.Logger == nil
+		if baseDir == "" && opts.BridgePath != "" {
+			baseDir = filepath.Dir(opts.BridgePath)
+		}
 	}
 	if baseDir == "" {
 		baseDir, _ = os.Getwd()
@@ -234,13 +757,36 @@ func resolveOpts(opts *Options) (string, bool) {
 	return baseDir, debug
 }
 
+// bridgePath returns the bridge library to load: Options.BridgePath if set,
+// else the library named for this platform in baseDir.
+func bridgePath(opts *Options, baseDir string) string {
+	if opts != nil && opts.BridgePath != "" {
+		return opts.BridgePath
+	}
+	return filepath.Join(baseDir, bridgeLibName())
+}
+
 // SetFocus gives this UI keyboard focus. Only the focused UI receives key events,
 // regardless of cursor position. Mouse and scroll still require the cursor inside bounds.
-// Clicking inside a UI also gives it focus.
+// Clicking inside a UI also gives it focus. No-op if the UI is not focusable.
 func (ui *UltralightUI) SetFocus() {
+	if ui.unfocusable {
+		return
+	}
 	setFocusedViewID(ui.viewID)
 }
 
+// SetFocusable sets whether the UI can take keyboard focus (default true).
+// A non-focusable UI, e.g. a decorative HUD overlay, still receives mouse
+// input, but clicking it doesn't steal focus from other views or the game,
+// and SetFocus does nothing. Making a focused UI non-focusable clears its focus.
+func (ui *UltralightUI) SetFocusable(focusable bool) {
+	ui.unfocusable = !focusable
+	if !focusable && getFocusedViewID() == ui.viewID {
+		setFocusedViewID(-1)
+	}
+}
+
 // SetBounds sets the screen rectangle for this UI. Mouse and scroll are only
 // forwarded when the cursor is inside these bounds. Keyboard goes to the focused UI.
 // Use (0,0,0,0) to disable input.
@@ -254,10 +800,14 @@ func (ui *UltralightUI) MarkDirty() {}
 
 // injectGoHelper installs a custom undo/redo system for input/textarea elements,
 // triggered from Go via ui.Eval("__ulUndo()") / "__ulRedo()" / "__ulSelectAll()".
-// JS→Go messaging uses the native __goSend JSC callback registered by the C bridge
-// in setup_js_bindings(). common.js wraps it as window.go.send().
+// JS→Go messaging uses the native __goSend JSC callback and the window.go.send
+// wrapper, both installed by the C bridge in setup_js_bindings() before the
+// page's first script runs (WindowObjectReady), so it doesn't depend on this.
 func (ui *UltralightUI) injectGoHelper() {
-	ui.Eval(`(function(){
+	if ui.jsDisabled {
+		return
+	}
+	evalJS(ui.viewID, `(function(){
 if(window.__ulUndoInit)return;window.__ulUndoInit=1;
 var stacks=new WeakMap(),redos=new WeakMap(),skip=0;
 function S(e){if(!stacks.has(e))stacks.set(e,[{v:e.value,s:e.selectionStart,e:e.selectionEnd}]);return stacks.get(e)}
@@ -307,20 +857,83 @@ func Tick() {
 // Note: each call to Update() triggers a full renderer cycle for ALL views.
 // For multiple views, prefer calling Tick() once then UpdateNoTick() on each view.
 func (ui *UltralightUI) Update() error {
-	if ui.closed {
-		return nil
-	}
-	ulTick()
-	return ui.updateInternal()
+	if ok, err := ui.beginUpdate(); !ok {
+		return err
+	}
+	defer ui.endUpdate()
+	start := time.Now()
+	ui.intervalSkip = !ui.refreshDue(start)
+	if !ui.shouldSkipFrame(ui.frameCount+1) && !ui.intervalSkip {
+		ulTick()
+	}
+	err := ui.updateInternal()
+	ui.recordFrameCost(time.Since(start))
+	return err
 }
 
 // UpdateNoTick does everything Update() does EXCEPT calling ulTick().
 // Use with Tick(): call Tick() once per frame, then UpdateNoTick() on each view.
 func (ui *UltralightUI) UpdateNoTick() error {
-	if ui.closed {
-		return nil
+	if ok, err := ui.beginUpdate(); !ok {
+		return err
+	}
+	defer ui.endUpdate()
+	start := time.Now()
+	ui.intervalSkip = !ui.refreshDue(start)
+	err := ui.updateInternal()
+	ui.recordFrameCost(time.Since(start))
+	return err
+}
+
+// beginUpdate marks an Update in progress. It returns false with
+// ErrConcurrentUpdate if another one is running, or with nil if the view is closed.
+func (ui *UltralightUI) beginUpdate() (bool, error) {
+	if ui.closed.Load() {
+		return false, nil
+	}
+	if !ui.updating.CompareAndSwap(false, true) {
+		return false, ErrConcurrentUpdate
+	}
+	// Re-check after publishing updating: a concurrent Close either sees the
+	// Update in progress or is seen here.
+	if ui.closed.Load() {
+		ui.endUpdate()
+		return false, nil
+	}
+	return true, nil
+}
+
+// endUpdate finishes an Update. If Close ran meanwhile, the view is destroyed now.
+func (ui *UltralightUI) endUpdate() {
+	ui.updating.Store(false)
+	if ui.closed.Load() {
+		ui.destroy()
 	}
-	return ui.updateInternal()
+}
+
+// SetPaused pauses or resumes the view. A paused view is skipped by the bridge
+// on every tick: no input or Eval processing and no animation frames, so an
+// offscreen panel stops consuming CPU/GPU. Update still drains go.send messages
+// while paused, and Evals queued during the pause run on resume.
+// Note that JS timers keep running, since the renderer updates all views together.
+func (ui *UltralightUI) SetPaused(paused bool) {
+	if ui.closed.Load() || ui.paused == paused {
+		return
+	}
+	ui.paused = paused
+	if ulViewSetPaused == nil {
+		return // older bridge: Update still skips input and pixel copies
+	}
+	p := int32(0)
+	if paused {
+		p = 1
+	}
+	ulViewSetPaused(ui.viewID, p)
+}
+
+// IsPaused reports whether the view was paused with SetPaused.
+func (ui *UltralightUI) IsPaused() bool {
+	return ui.paused
 }
 
 // isHidden returns true if the view has zero-size bounds (hidden via SetBounds(0,0,0,0)).
@@ -330,38 +943,87 @@ func (ui *UltralightUI) isHidden() bool {
 
 func (ui *UltralightUI) updateInternal() error {
 	ui.frameCount++
+	ui.stats.RenderedThisFrame = false
+	// Deliver Sends queued since the last update (and by OnMessage handlers below).
+	defer ui.flushSends()
 
 	// Poll native messages (JS -> Go via go.send) — always, even if hidden
-	for {
-		msg, ok := pollMessage(ui.viewID)
-		if !ok {
-			break
-		}
-		// Interceptar mensajes de focus de input (no reenviar a OnMessage)
-		if ui.handleInputFocusMsg(msg) {
-			continue
-		}
-		if ui.OnMessage != nil {
-			ui.OnMessage(msg)
-		}
-	}
+	ui.pollMessages()
+	ui.pollConsole()
+	ui.pollLoadEvents()
 
-	if !ui.domReady && ui.frameCount > 10 && ui.IsReady() {
+	if !ui.domReady && ui.checkDOMReady() {
 		ui.domReady = true
 	}
 
 	if ui.domReady && !ui.goHelperInjected {
-		ui.injectGoHelper()
+		if !ui.disableEditHelpers {
+			ui.injectGoHelper()
+		}
 		ui.goHelperInjected = true
+		ui.flushPreReady()
+	}
+
+	if ui.domReady && ui.OnDownload != nil && !ui.downloadHelperInjected {
+		ui.injectDownloadHelper()
+		ui.downloadHelperInjected = true
+	}
+
+	// Zoom set before the DOM was ready (1.0 needs no CSS)
+	if ui.domReady && ui.zoom != 0 && !ui.zoomApplied {
+		ui.applyZoom()
+	}
+
+	if ui.domReady && (ui.textDir != "" || ui.locale != "") && !ui.localeApplied {
+		ui.applyLocale()
+	}
+
+	if ui.domReady {
+		ui.applyFonts()
+	}
+
+	if ui.domReady && ui.modsSet && !ui.modsApplied {
+		ui.applyModifiers()
+	}
+
+	if ui.domReady && len(ui.pendingCSS) > 0 {
+		ui.applyPendingCSS()
+	}
+
+	if ui.domReady && ui.OnNavigationRequest != nil && !ui.navigationHelperInjected {
+		ui.injectNavigationHelper()
+		ui.navigationHelperInjected = true
+	}
+
+	if ui.domReady && ui.OnResourceRequest != nil && !ui.resourceHelperInjected {
+		ui.injectResourceHelper()
+		ui.resourceHelperInjected = true
+	}
+
+	if ui.domReady && ui.OnOverflow != nil && !ui.overflowHelperInjected {
+		ui.injectOverflowHelper()
+		ui.overflowHelperInjected = true
+	}
+
+	if ui.domReady && ui.animTracking && !ui.animHelperInjected {
+		ui.injectAnimationHelper()
+		ui.animHelperInjected = true
+	}
+
+	if ui.domReady && !ui.readyFired {
+		ui.fireReady()
 	}
 
 	// Re-check closed: an OnMessage callback above may have called Close().
-	if ui.closed {
+	if ui.closed.Load() {
 		return nil
 	}
 
-	// Hidden view: only drain messages, skip input processing and pixel copying
-	if ui.isHidden() {
+	// Hidden or paused view: only drain messages, skip input processing and pixel copying
+	if ui.isHidden() || ui.paused {
+		if ui.isHidden() {
+			ui.wasHidden = true
+		}
 		return nil
 	}
 
@@ -369,15 +1031,135 @@ func (ui *UltralightUI) updateInternal() error {
 		ui.forwardInput()
 	}
 
+	// First frame after being hidden (or after LockPixels): copy even without
+	// dirty bounds, so the texture never shows a frame from before the view
+	// was hidden.
+	if ui.wasHidden || ui.forceCopy {
+		ui.wasHidden, ui.forceCopy = false, false
+		ui.presentFrame(ui.copyFrameForced())
+		return nil
+	}
+
+	// Throttled quality mode or between SetUpdateInterval refreshes: keep input
+	// and messages responsive, skip the copy.
+	if ui.shouldSkipFrame(ui.frameCount) || ui.intervalSkip {
+		return nil
+	}
+
 	// Copy pixels only if Ultralight has rendered changes (dirty bounds).
-	// ul_view_copy_pixels_rgba internally checks if the surface changed;
-	// if no changes, returns 0 without copying (very cheap: just reads a rect).
-	if len(ui.pixels) > 0 && ui.texture != nil {
-		if ulViewCopyPixelsRGBA(ui.viewID, uintptr(unsafe.Pointer(&ui.pixels[0])), int32(len(ui.pixels))) != 0 {
+	ui.presentFrame(ui.copyFrame())
+	return nil
+}
+
+// presentFrame uploads a newly copied frame and records it in the stats.
+// The texture is only uploaded once GetTexture has created it.
+func (ui *UltralightUI) presentFrame(copied bool) {
+	if !copied {
+		return
+	}
+	if ui.texture != nil {
+		ui.presentPixels()
+	}
+	ui.recordPixelCopy(ui.dirty.Dx() * ui.dirty.Dy() * 4)
+}
+
+// pollMessages delivers every message queued by the page (go.send) to the
+// internal handlers or OnMessage.
+func (ui *UltralightUI) pollMessages() {
+	for {
+		msg, ok := pollMessage(ui.viewID)
+		if !ok {
+			return
+		}
+		// Interceptar mensajes de focus de input (no reenviar a OnMessage)
+		if ui.handleInputFocusMsg(msg) {
+			continue
+		}
+		if ui.handleDownloadMsg(msg) {
+			continue
+		}
+		if ui.handleOverflowMsg(msg) {
+			continue
+		}
+		if ui.handleNavigateMsg(msg) {
+			continue
+		}
+		if ui.handleResourceMsg(msg) {
+			continue
+		}
+		if ui.handleAnimationMsg(msg) {
+			continue
+		}
+		ui.dispatchMessage(msg)
+	}
+}
+
+// Drain delivers the messages the page has sent with go.send but that no
+// Update has polled yet, without ticking the renderer. Close calls it, so a
+// page that sends its final state and then asks to be closed loses nothing.
+func (ui *UltralightUI) Drain() {
+	if ui.closed.Load() {
+		return
+	}
+	ui.pollMessages()
+	ui.flushSends()
+}
+
+// fireReady runs Options.WarmupScript and then OnReady, once the DOM is ready.
+func (ui *UltralightUI) fireReady() {
+	ui.readyFired = true
+	if ui.warmupScript != "" {
+		ui.Eval(ui.warmupScript)
+	}
+	if ui.OnReady != nil {
+		ui.OnReady()
+	}
+}
+
+// anyButtonDown reports whether a press that started inside the view is still
+// held, which keeps input captured by the view ("mouse capture").
+func (ui *UltralightUI) anyButtonDown() bool {
+	return ui.leftDown || ui.rightDown || ui.middleDown || ui.extraDown[0] || ui.extraDown[1]
+}
+
+// extraMouseButtons are Ebiten's back and forward buttons, reported to
+// OnMouseButton as buttons 4 and 5.
+var extraMouseButtons = [2]ebiten.MouseButton{ebiten.MouseButton3, ebiten.MouseButton4}
+
+// forwardExtraButtons reports back/forward button presses inside the bounds,
+// and their releases, to OnMouseButton.
+func (ui *UltralightUI) forwardExtraButtons(inBounds bool, lx, ly int) {
+	for i, b := range extraMouseButtons {
+		pressed := ebiten.IsMouseButtonPressed(b)
+		if inBounds && inpututil.IsMouseButtonJustPressed(b) && !ui.extraDown[i] {
+			ui.extraDown[i] = true
+			if ui.OnMouseButton != nil {
+				ui.OnMouseButton(4+i, true, lx, ly)
+			}
+		} else if !pressed && ui.extraDown[i] {
+			ui.extraDown[i] = false
+			if ui.OnMouseButton != nil {
+				ui.OnMouseButton(4+i, false, lx, ly)
+			}
+		}
+	}
+}
+
+// presentPixels uploads ui.pixels, or only the rect the last copy changed.
+// With Options.DoubleBuffer the frame is written to the back texture and then
+// swapped in, so the texture returned by GetTexture is never the one being written.
+func (ui *UltralightUI) presentPixels() {
+	if ui.backTexture == nil {
+		if ui.dirty == ui.bounds() {
 			ui.texture.WritePixels(ui.pixels)
+			return
 		}
+		ui.dirtyBuf = ui.dirtyPixels(ui.dirtyBuf)
+		ui.texture.SubImage(ui.dirty).(*ebiten.Image).WritePixels(ui.dirtyBuf)
+		return
 	}
-	return nil
+	ui.backTexture.WritePixels(ui.pixels)
+	ui.texture, ui.backTexture = ui.backTexture, ui.texture
 }
 
 func (ui *UltralightUI) inBounds(mx, my int) bool {
@@ -388,12 +1170,75 @@ func (ui *UltralightUI) inBounds(mx, my int) bool {
 		my >= ui.BoundsY && my < ui.BoundsY+ui.BoundsH
 }
 
+// viewCoords converts offset-adjusted screen coordinates to view-local CSS
+// pixels (relative to the bounds origin).
+func (ui *UltralightUI) viewCoords(mx, my int) (int, int) {
+	if ui.BoundsW <= 0 {
+		return mx, my
+	}
+	return mx - ui.BoundsX, my - ui.BoundsY
+}
+
+// toLocal converts offset-adjusted screen coordinates to the coordinates
+// Ultralight expects for mouse events (view-local, scaled for HiDPI).
+func (ui *UltralightUI) toLocal(mx, my int) (int, int) {
+	return ui.scaleLocal(ui.viewCoords(mx, my))
+}
+
+// edgeLocal is toLocal with the point clamped to the view: the edge point a
+// cursor now at mx, my crossed on its way out.
+func (ui *UltralightUI) edgeLocal(mx, my int) (int, int) {
+	x, y := ui.viewCoords(mx, my)
+	x = min(max(x, 0), ui.width-1)
+	y = min(max(y, 0), ui.height-1)
+	return ui.scaleLocal(x, y)
+}
+
+func (ui *UltralightUI) scaleLocal(x, y int) (int, int) {
+	// Escalar coordenadas locales para HiDPI (macOS Retina u otros)
+	if scale := ui.getMouseScale(); scale > 1.0 {
+		x = int(float64(x) * scale)
+		y = int(float64(y) * scale)
+	}
+	return x, y
+}
+
+// fireMouseMove sends a move to lx, ly if the cursor moved, or always when it
+// just entered the view, so mouseenter and :hover apply at the entry point
+// before any button event. Passes the left button so Ultralight can handle
+// drag-selection in inputs.
+func (ui *UltralightUI) fireMouseMove(lx, ly int, entered bool) {
+	if !entered && lx == ui.mouseX && ly == ui.mouseY {
+		return
+	}
+	moveBtn := int32(MouseButtonNone)
+	if ui.leftDown {
+		moveBtn = MouseButtonLeft
+	}
+	ulViewFireMouse(ui.viewID, MouseEventMoved, int32(lx), int32(ly), moveBtn)
+	ui.mouseX = lx
+	ui.mouseY = ly
+}
+
+// fireMouseLeave moves the page's cursor to the edge point where it left the
+// view, so mousemove/mouseleave handlers see the real exit coordinate, then
+// off the view (-1, -1) so :hover clears.
+func (ui *UltralightUI) fireMouseLeave(mx, my int) {
+	ui.mouseInside = false
+	if ex, ey := ui.edgeLocal(mx, my); ex != ui.mouseX || ey != ui.mouseY {
+		ulViewFireMouse(ui.viewID, MouseEventMoved, int32(ex), int32(ey), MouseButtonNone)
+	}
+	ulViewFireMouse(ui.viewID, MouseEventMoved, -1, -1, MouseButtonNone)
+	ui.mouseX = -1
+	ui.mouseY = -1
+}
+
 func (ui *UltralightUI) forwardInput() {
 	mx, my := ebiten.CursorPosition()
 	rawMx, rawMy := mx, my // guardamos para debug
 	mx -= GlobalCursorOffsetX
 	my -= GlobalCursorOffsetY
-	inBounds := ui.inBounds(mx, my)
+	inBounds := ui.hitTest(mx, my)
 	// Si la vista esta ocluida por otra encima, se comporta como si el cursor
 	// estuviera fuera de sus bounds: no recibe clicks, move ni scroll nuevos.
 	// Los press iniciados previamente dentro (leftDown/rightDown) mantienen la
@@ -401,10 +1246,31 @@ func (ui *UltralightUI) forwardInput() {
 	if ui.BlockInput {
 		inBounds = false
 	}
+	// A press on an empty page area (SetClickThroughEmpty) goes to the game:
+	// the cursor stays inside for hover and moves, only the press is skipped.
+	passPress := inBounds && ui.passEmptyPress(mx, my,
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft),
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight),
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle))
+
+	ui.forwardPinch(ui.BlockInput)
+
+	// Scroll solo dentro de bounds; el smooth scroll en curso sigue avanzando.
+	_, scrollY := ebiten.Wheel()
+	ui.forwardScroll(inBounds && ui.takesScroll(), scrollY)
+
+	// Files dropped from the OS go to the view under the cursor.
+	if inBounds {
+		if files := ebiten.DroppedFiles(); files != nil {
+			ui.handleFileDrop(files, mx, my)
+		}
+	}
 
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		if inBounds {
-			setFocusedViewID(ui.viewID)
+		if inBounds && !passPress {
+			if ui.takesFocusOnClick() {
+				ui.SetFocus()
+			}
 		} else if getFocusedViewID() == ui.viewID {
 			// Click fuera de esta vista que tenia foco: liberar foco para que
 			// las teclas (flechas, etc.) no sigan llegando al HTML.
@@ -415,42 +1281,23 @@ func (ui *UltralightUI) forwardInput() {
 	// "Mouse capture": si el press inicio dentro de esta vista, seguimos
 	// reenviando eventos aunque el cursor salga de los bounds, hasta que
 	// se suelte el boton (igual que el comportamiento nativo de un browser).
-	captured := ui.leftDown || ui.rightDown
+	captured := ui.anyButtonDown()
 
 	if inBounds || captured {
+		entered := inBounds && !ui.mouseInside
 		if inBounds {
 			ui.mouseInside = true
 		}
-		lx := mx - ui.BoundsX
-		ly := my - ui.BoundsY
-		if ui.BoundsW <= 0 {
-			lx, ly = mx, my
-		}
-
-		// Escalar coordenadas locales para HiDPI (macOS Retina u otros)
-		scale := ui.getMouseScale()
-		if scale > 1.0 {
-			lx = int(float64(lx) * scale)
-			ly = int(float64(ly) * scale)
-		}
+		lx, ly := ui.toLocal(mx, my)
 
 		// Debug logging: solo en clicks para no spamear
 		if DebugInput && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 			log.Printf("[ultralightui] click viewID=%d cursor=(%d,%d) offset=(%d,%d) adjusted=(%d,%d) bounds=(%d,%d,%d,%d) local=(%d,%d) scale=%.1f",
 				ui.viewID, rawMx, rawMy, GlobalCursorOffsetX, GlobalCursorOffsetY,
-				mx, my, ui.BoundsX, ui.BoundsY, ui.BoundsW, ui.BoundsH, lx, ly, scale)
+				mx, my, ui.BoundsX, ui.BoundsY, ui.BoundsW, ui.BoundsH, lx, ly, ui.getMouseScale())
 		}
 
-		if lx != ui.mouseX || ly != ui.mouseY {
-			// Pass current button state so Ultralight can handle drag-selection in inputs.
-			moveBtn := int32(mouseButtonNone)
-			if ui.leftDown {
-				moveBtn = mouseButtonLeft
-			}
-			ulViewFireMouse(ui.viewID, mouseEventTypeMoved, int32(lx), int32(ly), moveBtn)
-			ui.mouseX = lx
-			ui.mouseY = ly
-		}
+		ui.fireMouseMove(lx, ly, entered)
 
 		// Left button — use JustPressed to catch sub-frame clicks (macOS trackpad)
 		justPressedLeft := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
@@ -460,18 +1307,24 @@ func (ui *UltralightUI) forwardInput() {
 		if inBounds {
 			if justPressedLeft && !ui.leftDown && !ui.leftOutside {
 				ui.leftDown = true
-				ulViewFireMouse(ui.viewID, mouseEventTypeDown, int32(lx), int32(ly), mouseButtonLeft)
+				ui.clicks.press(time.Now(), lx, ly)
+				ulViewFireMouse(ui.viewID, MouseEventDown, int32(lx), int32(ly), MouseButtonLeft)
 			} else if pressedLeft && !ui.leftDown && !ui.leftOutside {
 				// Button held from previous frame without JustPressed (edge case)
 				ui.leftDown = true
-				ulViewFireMouse(ui.viewID, mouseEventTypeDown, int32(lx), int32(ly), mouseButtonLeft)
+				ui.clicks.press(time.Now(), lx, ly)
+				ulViewFireMouse(ui.viewID, MouseEventDown, int32(lx), int32(ly), MouseButtonLeft)
 			}
 		}
 
 		if !pressedLeft {
 			if ui.leftDown {
 				ui.leftDown = false
-				ulViewFireMouse(ui.viewID, mouseEventTypeUp, int32(lx), int32(ly), mouseButtonLeft)
+				ulViewFireMouse(ui.viewID, MouseEventUp, int32(lx), int32(ly), MouseButtonLeft)
+				// Runs after the up in the same bridge tick (JS queue follows input).
+				if ui.clicks.count >= 2 {
+					ui.Eval(multiClickScript(ui.clicks.count, ui.clicks.x, ui.clicks.y))
+				}
 			}
 			ui.leftOutside = false
 		}
@@ -483,45 +1336,51 @@ func (ui *UltralightUI) forwardInput() {
 		if inBounds {
 			if justPressedRight && !ui.rightDown && !ui.rightOutside {
 				ui.rightDown = true
-				ulViewFireMouse(ui.viewID, mouseEventTypeDown, int32(lx), int32(ly), mouseButtonRight)
+				ulViewFireMouse(ui.viewID, MouseEventDown, int32(lx), int32(ly), MouseButtonRight)
 			} else if pressedRight && !ui.rightDown && !ui.rightOutside {
 				ui.rightDown = true
-				ulViewFireMouse(ui.viewID, mouseEventTypeDown, int32(lx), int32(ly), mouseButtonRight)
+				ulViewFireMouse(ui.viewID, MouseEventDown, int32(lx), int32(ly), MouseButtonRight)
 			}
 		}
 
 		if !pressedRight {
 			if ui.rightDown {
 				ui.rightDown = false
-				ulViewFireMouse(ui.viewID, mouseEventTypeUp, int32(lx), int32(ly), mouseButtonRight)
+				ulViewFireMouse(ui.viewID, MouseEventUp, int32(lx), int32(ly), MouseButtonRight)
 			}
 			ui.rightOutside = false
 		}
 
-		// Scroll solo dentro de bounds
+		// Middle button — same pattern
+		justPressedMiddle := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle)
+		pressedMiddle := ebiten.IsMouseButtonPressed(ebiten.MouseButtonMiddle)
+
 		if inBounds {
-			_, scrollY := ebiten.Wheel()
-			if scrollY != 0 {
-				ulViewFireScroll(ui.viewID, scrollEventTypeByPixel, 0, int32(scrollY*100))
+			if (justPressedMiddle || pressedMiddle) && !ui.middleDown && !ui.middleOutside {
+				ui.middleDown = true
+				ulViewFireMouse(ui.viewID, MouseEventDown, int32(lx), int32(ly), MouseButtonMiddle)
 			}
 		}
 
-		// Si termino la captura y estamos fuera de bounds, enviar leave
-		if !inBounds && !ui.leftDown && !ui.rightDown {
-			if ui.mouseInside {
-				ui.mouseInside = false
-				ulViewFireMouse(ui.viewID, mouseEventTypeMoved, -1, -1, mouseButtonNone)
-				ui.mouseX = -1
-				ui.mouseY = -1
+		if !pressedMiddle {
+			if ui.middleDown {
+				ui.middleDown = false
+				ulViewFireMouse(ui.viewID, MouseEventUp, int32(lx), int32(ly), MouseButtonMiddle)
 			}
+			ui.middleOutside = false
+		}
+
+		// Back/forward buttons have no Ultralight equivalent: report them to the host.
+		ui.forwardExtraButtons(inBounds, lx, ly)
+
+		// Si termino la captura y estamos fuera de bounds, enviar leave
+		if !inBounds && !ui.anyButtonDown() && ui.mouseInside {
+			ui.fireMouseLeave(mx, my)
 		}
 	} else {
 		// Cursor fuera de bounds y sin captura
 		if ui.mouseInside {
-			ui.mouseInside = false
-			ulViewFireMouse(ui.viewID, mouseEventTypeMoved, -1, -1, mouseButtonNone)
-			ui.mouseX = -1
-			ui.mouseY = -1
+			ui.fireMouseLeave(mx, my)
 		}
 		// Cursor outside bounds: if button is pressed outside, mark to ignore on re-enter
 		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
@@ -540,75 +1399,169 @@ func (ui *UltralightUI) forwardInput() {
 			ui.rightOutside = false
 			ui.rightDown = false
 		}
+		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonMiddle) {
+			if !ui.middleDown {
+				ui.middleOutside = true
+			}
+		} else {
+			ui.middleOutside = false
+			ui.middleDown = false
+		}
 	}
 
-	if getFocusedViewID() == ui.viewID {
+	if ui.takesKeyboard(inBounds) {
 		ui.forwardKeyboard()
 	}
 }
 
-// Key repeat timing in frames (at 60fps: delay ~500ms, interval ~33ms).
+// Default key repeat timing (Options.KeyRepeatDelayMs / KeyRepeatIntervalMs),
+// the former 30 and 2 frames at 60 TPS.
 const (
-	keyRepeatDelay    = 30 // frames before repeat starts
-	keyRepeatInterval = 2  // frames between repeats
+	defaultKeyRepeatDelay    = 500 * time.Millisecond
+	defaultKeyRepeatInterval = 33 * time.Millisecond
 )
 
+// keyRepeats returns how many synthetic repeats are due on the tick a key has
+// been held for dur ticks. Timing is converted from ticks with tps, so repeat
+// speed is the same at any tick rate; at low TPS several repeats may be due
+// on one tick.
+func keyRepeats(dur int, tps float64, delay, interval time.Duration) int {
+	if dur <= 1 || tps <= 0 || interval <= 0 {
+		return 0
+	}
+	due := func(ticks int) int {
+		held := time.Duration(float64(ticks) / tps * float64(time.Second))
+		if held < delay {
+			return 0
+		}
+		return 1 + int((held-delay)/interval)
+	}
+	return due(dur) - due(dur-1)
+}
+
+// tickRate returns the tick rate KeyPressDuration counts in.
+func tickRate() float64 {
+	if tps := ebiten.TPS(); tps > 0 {
+		return float64(tps)
+	}
+	// SyncWithFPS: one tick per frame
+	if tps := ebiten.ActualTPS(); tps > 0 {
+		return tps
+	}
+	return ebiten.DefaultTPS
+}
+
+// repeatDelay and repeatInterval return the key repeat timing of the view.
+func (ui *UltralightUI) repeatDelay() time.Duration {
+	if ui.keyRepeatDelay > 0 {
+		return ui.keyRepeatDelay
+	}
+	return defaultKeyRepeatDelay
+}
+
+func (ui *UltralightUI) repeatInterval() time.Duration {
+	if ui.keyRepeatInterval > 0 {
+		return ui.keyRepeatInterval
+	}
+	return defaultKeyRepeatInterval
+}
+
 func (ui *UltralightUI) forwardKeyboard() {
 	ctrlHeld := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	altHeld := ebiten.IsKeyPressed(ebiten.KeyAlt)
+	shiftHeld := ebiten.IsKeyPressed(ebiten.KeyShift)
 
-	// Key down events (RawKeyDown triggers accelerators like Ctrl+C/V/X)
+	// Key down events (RawKeyDown triggers accelerators like Ctrl+C/V/X).
+	// RawKeyDown never inserts text: typed characters, including dead-key and
+	// compose results, only come from the Char events below.
 	// Reuse buffer to avoid per-frame allocations
 	ui.keyBuf = inpututil.AppendJustPressedKeys(ui.keyBuf[:0])
 	for _, key := range ui.keyBuf {
 		// Intercept editing shortcuts and handle via JS (Ultralight's native
 		// key_identifier support through ulCreateKeyEvent is unreliable).
-		if ctrlHeld {
-			switch key {
-			case ebiten.KeyZ:
-				if ebiten.IsKeyPressed(ebiten.KeyShift) {
-					ui.Eval("if(window.__ulRedo)__ulRedo()")
-				} else {
-					ui.Eval("if(window.__ulUndo)__ulUndo()")
-				}
-				continue
-			case ebiten.KeyY:
-				ui.Eval("if(window.__ulRedo)__ulRedo()")
-				continue
-			case ebiten.KeyA:
-				ui.Eval("if(window.__ulSelectAll)__ulSelectAll()")
+		// Match on the layout's logical key so Ctrl+Z follows the key labelled Z.
+		vk, mods, text := keyToVK(key)
+		if ctrlHeld && !ui.disableEditHelpers {
+			if js := editShortcut(vk, altHeld, shiftHeld); js != "" {
+				ui.Eval(js)
 				continue
 			}
 		}
-		vk, mods := keyToVK(key)
 		if vk != 0 {
-			ulViewFireKey(ui.viewID, keyEventRawKeyDown, vk, mods, vkToChar(vk))
+			ulViewFireKey(ui.viewID, KeyEventRawKeyDown, vk, mods, text)
+			ui.fireTabChar(key)
 		}
 	}
 	// Key repeat: re-fire RawKeyDown for held non-character keys (Backspace, Delete, arrows, etc.)
+	tps := tickRate()
 	for _, key := range heldNonCharKeys {
-		dur := inpututil.KeyPressDuration(key)
-		if dur > keyRepeatDelay && (dur-keyRepeatDelay)%keyRepeatInterval == 0 {
-			vk, mods := keyToVK(key)
-			if vk != 0 {
-				ulViewFireKey(ui.viewID, keyEventRawKeyDown, vk, mods, vkToChar(vk))
-			}
+		n := keyRepeats(inpututil.KeyPressDuration(key), tps, ui.repeatDelay(), ui.repeatInterval())
+		if n == 0 {
+			continue
+		}
+		vk, mods, text := keyToVK(key)
+		for ; vk != 0 && n > 0; n-- {
+			ulViewFireKey(ui.viewID, KeyEventRawKeyDown, vk, mods, text)
+			ui.fireTabChar(key)
 		}
 	}
 	// Character input from OS text input system (handles shift, layout, IME correctly)
 	ui.charBuf = ebiten.AppendInputChars(ui.charBuf[:0])
 	for _, r := range ui.charBuf {
-		if r >= 0x20 { // filter control characters (Ctrl+letter combos)
-			ulViewFireKey(ui.viewID, keyEventChar, 0, 0, string(r))
+		// '\t' comes from fireTabChar, so platforms that also report it don't type it twice.
+		if r != '\t' && ui.acceptsChar(r) {
+			ulViewFireKey(ui.viewID, KeyEventChar, 0, 0, string(r))
 		}
 	}
 	// Key up events
 	ui.keyBuf = inpututil.AppendJustReleasedKeys(ui.keyBuf[:0])
 	for _, key := range ui.keyBuf {
-		vk, mods := keyToVK(key)
+		vk, mods, text := keyToVK(key)
 		if vk != 0 {
-			ulViewFireKey(ui.viewID, keyEventKeyUp, vk, mods, vkToChar(vk))
+			ulViewFireKey(ui.viewID, KeyEventKeyUp, vk, mods, text)
+		}
+	}
+}
+
+// acceptsChar reports whether r is sent to the page as character input
+// (Options.CharFilter). By default control characters (Ctrl+letter combos)
+// and DEL are dropped.
+func (ui *UltralightUI) acceptsChar(r rune) bool {
+	if ui.charFilter != nil {
+		return ui.charFilter(r)
+	}
+	return r >= 0x20 && r != 0x7F
+}
+
+// fireTabChar types a tab after a Tab key down or repeat when CharFilter
+// accepts '\t'. Tab is in heldNonCharKeys, so held Tabs repeat like Backspace.
+func (ui *UltralightUI) fireTabChar(key ebiten.Key) {
+	if key == ebiten.KeyTab && ui.acceptsChar('\t') {
+		ulViewFireKey(ui.viewID, KeyEventChar, 0, 0, "\t")
+	}
+}
+
+// editShortcut returns the script for an intercepted Ctrl/Cmd editing shortcut
+// (undo, redo, select all), or "" if the key should go to Ultralight as is.
+// Ctrl+Alt is AltGr on Windows/Linux layouts (e.g. AltGr+A types "ą" on Polish
+// keyboards), so it never counts as a shortcut: the character must be typed
+// instead of selecting all the text it would then replace.
+func editShortcut(vk int32, alt, shift bool) string {
+	if alt {
+		return ""
+	}
+	switch vk {
+	case 0x5A: // Z
+		if shift {
+			return "if(window.__ulRedo)__ulRedo()"
 		}
+		return "if(window.__ulUndo)__ulUndo()"
+	case 0x59: // Y
+		return "if(window.__ulRedo)__ulRedo()"
+	case 0x41: // A
+		return "if(window.__ulSelectAll)__ulSelectAll()"
 	}
+	return ""
 }
 
 // vkToChar returns the lowercase character for a virtual key code.
@@ -635,7 +1588,10 @@ func vkToChar(vk int32) string {
 }
 
 // heldNonCharKeys lists keys that need synthetic repeat because the OS text input
-// system (AppendInputChars) does not emit characters for them.
+// system (AppendInputChars) does not emit characters for them. Only these keys
+// use the key repeat timing: their KeyPressDuration (in ticks) is converted to
+// time by keyRepeats, and the tick they are pressed on only sends the initial
+// RawKeyDown (from AppendJustPressedKeys), never a repeat.
 var heldNonCharKeys = []ebiten.Key{
 	ebiten.KeyBackspace,
 	ebiten.KeyDelete,
@@ -652,22 +1608,24 @@ var heldNonCharKeys = []ebiten.Key{
 	ebiten.KeyEscape,
 }
 
-func keyToVK(key ebiten.Key) (int32, uint32) {
+// keyToVK returns the virtual key, modifier flags and unmodified text for a
+// physical key, using the layout set by SetKeyboardLayout.
+func keyToVK(key ebiten.Key) (int32, uint32, string) {
 	mods := uint32(0)
 	if ebiten.IsKeyPressed(ebiten.KeyShift) {
-		mods |= keyModShift
+		mods |= KeyModShift
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyControl) {
-		mods |= keyModCtrl
+		mods |= KeyModCtrl
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyAlt) {
-		mods |= keyModAlt
+		mods |= KeyModAlt
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyMeta) {
-		mods |= keyModMeta
+		mods |= KeyModMeta
 	}
-	vk := ebitenKeyToVK(key)
-	return vk, mods
+	vk, text := mapKey(key)
+	return vk, mods, text
 }
 
 func ebitenKeyToVK(key ebiten.Key) int32 {
@@ -815,20 +1773,145 @@ func ebitenKeyToVK(key ebiten.Key) int32 {
 }
 
 // GetTexture returns the Ebiten image with the current HTML content rendered.
-// Returns nil if the UI has been closed.
+// The image is created on the first call, so call it from Ebiten's Draw (or
+// once a graphics context exists). Returns nil if the UI has been closed.
 func (ui *UltralightUI) GetTexture() *ebiten.Image {
-	if ui.closed {
+	if ui.closed.Load() {
 		return nil
 	}
+	if ui.texture == nil {
+		ui.texture = ebiten.NewImage(ui.width, ui.height)
+		if ui.doubleBuffer {
+			ui.backTexture = ebiten.NewImage(ui.width, ui.height)
+		}
+		if ui.hasFrame {
+			ui.texture.WritePixels(ui.pixels)
+		}
+	}
 	return ui.texture
 }
 
+// DrawTo writes the current frame into dst with its top-left corner at
+// (x, y), relative to dst's bounds, e.g. a region of a texture atlas. It
+// writes the pixels directly, replacing what the region held (no blending),
+// and never allocates the view's own texture. The part of the view outside
+// dst is clipped. Each call uploads the visible area, so call it when
+// Stats().RenderedThisFrame reports a new frame rather than every Draw.
+// Does nothing before the first frame or after Close.
+func (ui *UltralightUI) DrawTo(dst *ebiten.Image, x, y int) {
+	if ui.closed.Load() || !ui.hasFrame || dst == nil {
+		return
+	}
+	to, from := ui.clipTo(dst.Bounds(), x, y)
+	if to.Empty() {
+		return
+	}
+	sub := dst.SubImage(to).(*ebiten.Image)
+	if from == ui.bounds() {
+		sub.WritePixels(ui.pixels)
+		return
+	}
+	ui.drawBuf = ui.rectPixels(ui.drawBuf, from)
+	sub.WritePixels(ui.drawBuf)
+}
+
+// Pixels returns the last frame copied from the view: width*height*4 bytes of
+// premultiplied RGBA, as uploaded to the texture. It is nil after Close.
+// The slice is reused: without Options.DoubleBuffer, it is overwritten by the
+// next Update that renders; with it, it stays intact through the next Update.
+func (ui *UltralightUI) Pixels() []byte {
+	if ui.closed.Load() {
+		return nil
+	}
+	return ui.pixels
+}
+
 // Eval runs JavaScript in the page. Fire-and-forget (no return value).
+// If send coalescing is enabled, pending Send payloads are flushed first so
+// the script observes them in call order. Scripts evaluated before the page
+// is ready are queued and run in order once it is (see WaitReady).
 func (ui *UltralightUI) Eval(script string) {
-	if ui.closed {
+	if ui.closed.Load() {
+		return
+	}
+	ui.flushSends()
+	ui.runScript(script)
+}
+
+// domReadyFallbackFrames is how many updates checkDOMReady waits before
+// trusting IsReady alone, for SDKs without a DOM-ready callback or pages
+// whose callback never arrives.
+const domReadyFallbackFrames = 10
+
+// checkDOMReady reports whether the view's document has been parsed: as soon
+// as the bridge's DOMReady signal fires, or after domReadyFallbackFrames
+// updates of a ready view.
+func (ui *UltralightUI) checkDOMReady() bool {
+	if !ui.IsReady() {
+		return false
+	}
+	return ui.frameCount > domReadyFallbackFrames ||
+		(ulViewIsDOMReady != nil && ulViewIsDOMReady(ui.viewID) == 1)
+}
+
+// scriptsReady reports whether the DOM is ready and the built-in helpers are
+// installed, so scripts from Eval and Send can run right away.
+func (ui *UltralightUI) scriptsReady() bool {
+	return ui.domReady && ui.goHelperInjected
+}
+
+// runScript evaluates js now if the page is ready, else queues it for
+// flushPreReady. Views without JavaScript drop it.
+func (ui *UltralightUI) runScript(js string) {
+	if ui.jsDisabled {
 		return
 	}
-	evalJS(ui.viewID, script)
+	if !ui.scriptsReady() {
+		ui.preReady = append(ui.preReady, js)
+		return
+	}
+	evalJS(ui.viewID, js)
+}
+
+// flushPreReady runs the scripts queued by runScript, in order.
+func (ui *UltralightUI) flushPreReady() {
+	for i, js := range ui.preReady {
+		evalJS(ui.viewID, js)
+		ui.preReady[i] = ""
+	}
+	ui.preReady = ui.preReady[:0]
+}
+
+// EvalSync runs JavaScript in the page and waits for its result, converted to
+// a string (objects should be JSON.stringify'd by the script). Pending Evals
+// and coalesced Sends run first, so the script observes them; Evals queued
+// before the page is ready stay queued until its helpers are in. It returns
+// ErrNotReady while the view is still loading, and an error carrying the
+// exception message if the script throws.
+func (ui *UltralightUI) EvalSync(script string) (string, error) {
+	if ui.closed.Load() {
+		return "", ErrClosed
+	}
+	if ui.jsDisabled {
+		return "", ErrJavaScriptDisabled
+	}
+	ui.flushSends()
+	if ui.scriptsReady() {
+		// Before the helpers are in, queued scripts wait for updateInternal.
+		ui.flushPreReady()
+	}
+	result, status := evalSync(ui.viewID, script)
+	switch {
+	case status == -2:
+		return "", ErrNotReady
+	case status == evalSyncUnsupported:
+		return "", errUnsupported("ul_view_eval_sync")
+	case status < 0:
+		return "", fmt.Errorf("EvalSync: bridge error %d", status)
+	case status == 1:
+		return "", fmt.Errorf("EvalSync: %s", result)
+	}
+	return result, nil
 }
 
 // ParseMessage attempts to parse msg as JSON. If parsing succeeds, the parsed
@@ -846,16 +1929,41 @@ func ParseMessage(msg string) (interface{}, error) {
 	return msg, nil
 }
 
+// Decode unmarshals a JSON message into a T, e.g. go.send({type:"buy",
+// item:"sword"}) into a struct with Type and Item fields. Unlike ParseMessage,
+// a payload that is not JSON (such as go.send("hello")) is an error.
+func Decode[T any](msg string) (T, error) {
+	var v T
+	trimmed := strings.TrimSpace(msg)
+	if !json.Valid([]byte(trimmed)) {
+		if len(trimmed) > 100 {
+			trimmed = trimmed[:100] + "..."
+		}
+		return v, fmt.Errorf("ultralightui: message is not JSON: %q", trimmed)
+	}
+	if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+		return v, fmt.Errorf("ultralightui: decoding message: %w", err)
+	}
+	return v, nil
+}
+
 // Send sends structured data to the page. It serializes to JSON and invokes
 // window.go.receive(data). Define go.receive in your HTML to handle it.
+//
+// With SetCoalesceSends(true), the payload is queued and delivered together
+// with the other Sends of the same frame at the end of Update (see SetCoalesceSends).
 func (ui *UltralightUI) Send(data interface{}) error {
-	if ui.closed {
+	if ui.closed.Load() {
 		return ErrClosed
 	}
 	jsonBytes, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("Send: %w", err)
 	}
+	if ui.coalesceSends {
+		ui.pendingSends = append(ui.pendingSends, jsonBytes)
+		return nil
+	}
 	// JSON es sintaxis JS valida: embeber directo sin escapar ni JSON.parse.
 	// Evita el loop byte-a-byte de escaping y el doble parsing en JS.
 	const prefix = "if(window.go&&typeof window.go.receive==='function')window.go.receive("
@@ -865,10 +1973,135 @@ func (ui *UltralightUI) Send(data interface{}) error {
 	sb.WriteString(prefix)
 	sb.Write(jsonBytes)
 	sb.WriteString(suffix)
-	evalJS(ui.viewID, sb.String())
+	ui.runScript(sb.String())
+	return nil
+}
+
+// SendBatch sends several named values in a single window.go.receive call.
+// The page receives one object whose keys are the keys of items, e.g.
+// SendBatch({"hp": 80, "mana": 40}) calls go.receive({hp: 80, mana: 40}).
+// Use it to push per-frame telemetry with one JS boundary crossing.
+func (ui *UltralightUI) SendBatch(items map[string]interface{}) error {
+	if ui.closed.Load() {
+		return ErrClosed
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	if err := ui.Send(items); err != nil {
+		return fmt.Errorf("SendBatch: %w", err)
+	}
+	return nil
+}
+
+// SendDiff sends only the top-level fields of data that changed since the last
+// SendDiff, calling window.go.patch(partial) in the page. data must marshal to
+// a JSON object. Fields that disappeared are sent as null. Nothing is sent if
+// no field changed. The first SendDiff (or the first after ResetDiff) sends
+// every field; use Send for state that go.patch should not merge.
+// Queued coalesced Sends are flushed first, so ordering with Send is preserved.
+func (ui *UltralightUI) SendDiff(data interface{}) error {
+	if ui.closed.Load() {
+		return ErrClosed
+	}
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("SendDiff: %w", err)
+	}
+	var next map[string]json.RawMessage
+	if err := json.Unmarshal(jsonBytes, &next); err != nil || next == nil {
+		return fmt.Errorf("SendDiff: data must be a JSON object")
+	}
+	patch := diffFields(ui.lastDiff, next)
+	ui.lastDiff = next
+	if len(patch) == 0 {
+		return nil
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("SendDiff: %w", err)
+	}
+	ui.flushSends()
+	ui.runScript("if(window.go&&typeof window.go.patch==='function')window.go.patch(" + string(patchJSON) + ");")
 	return nil
 }
 
+// ResetDiff forgets the state tracked by SendDiff, so the next SendDiff sends
+// every field (e.g. after the page reloads).
+func (ui *UltralightUI) ResetDiff() {
+	ui.lastDiff = nil
+}
+
+// diffFields returns the fields of next that are new or differ from prev, plus
+// a null for each field of prev missing from next.
+func diffFields(prev, next map[string]json.RawMessage) map[string]json.RawMessage {
+	patch := make(map[string]json.RawMessage)
+	for k, v := range next {
+		if old, ok := prev[k]; !ok || !bytes.Equal(old, v) {
+			patch[k] = v
+		}
+	}
+	for k := range prev {
+		if _, ok := next[k]; !ok {
+			patch[k] = json.RawMessage("null")
+		}
+	}
+	return patch
+}
+
+// SetCoalesceSends enables frame coalescing: Send calls made between two
+// updates are queued and delivered in a single JS eval at the end of the next
+// Update/UpdateNoTick, instead of one eval per Send.
+//
+// Ordering guarantees:
+//   - queued payloads reach go.receive in the order Send was called, one call each;
+//   - Eval flushes the queue before running, so Send-then-Eval keeps its order;
+//   - SendBinary uses a separate bridge queue and is not ordered relative to Send.
+//
+// Disabling coalescing flushes any queued payloads immediately.
+func (ui *UltralightUI) SetCoalesceSends(enabled bool) {
+	ui.coalesceSends = enabled
+	if !enabled {
+		ui.flushSends()
+	}
+}
+
+// flushSends delivers queued Send payloads with a single eval.
+func (ui *UltralightUI) flushSends() {
+	if len(ui.pendingSends) == 0 {
+		return
+	}
+	if ui.closed.Load() {
+		ui.pendingSends = ui.pendingSends[:0]
+		return
+	}
+	ui.runScript(buildReceiveBatch(ui.pendingSends))
+	for i := range ui.pendingSends {
+		ui.pendingSends[i] = nil
+	}
+	ui.pendingSends = ui.pendingSends[:0]
+}
+
+// buildReceiveBatch builds one script calling window.go.receive once per payload, in order.
+func buildReceiveBatch(payloads [][]byte) string {
+	const prefix = "(function(){if(!window.go||typeof window.go.receive!=='function')return;var r=window.go.receive;"
+	const suffix = "})();"
+	n := len(prefix) + len(suffix)
+	for _, p := range payloads {
+		n += len(p) + len("r();")
+	}
+	var sb strings.Builder
+	sb.Grow(n)
+	sb.WriteString(prefix)
+	for _, p := range payloads {
+		sb.WriteString("r(")
+		sb.Write(p)
+		sb.WriteString(");")
+	}
+	sb.WriteString(suffix)
+	return sb.String()
+}
+
 // SupportsBinarySend retorna true si el bridge nativo tiene los simbolos JSC
 // necesarios para SendBinary. Si false, el caller debe usar Send con base64.
 func SupportsBinarySend() bool {
@@ -886,9 +2119,12 @@ func SupportsBinarySend() bool {
 // Si el bridge no soporta el path binario (SupportsBinarySend() == false),
 // retorna error sin enviar nada — el caller deberia hacer fallback a Send.
 func (ui *UltralightUI) SendBinary(props map[string]interface{}, binKey string, binData []byte) error {
-	if ui.closed {
+	if ui.closed.Load() {
 		return ErrClosed
 	}
+	if ui.jsDisabled {
+		return nil
+	}
 	if !SupportsBinarySend() {
 		return errors.New("ultralightui: bridge does not support binary send (JSC API missing)")
 	}
@@ -912,11 +2148,42 @@ func (ui *UltralightUI) SendBinary(props map[string]interface{}, binKey string,
 	return nil
 }
 
+// PreferredSize measures the natural size of the page content in CSS pixels:
+// the max-content width of the document and its height at that width. Create
+// the view large enough, measure, then size your bounds to fit.
+func (ui *UltralightUI) PreferredSize() (w, h int, err error) {
+	return ui.measure(`(function(){var d=document.documentElement;if(!d)return'0,0';
+var sw=d.style.width,sh=d.style.height;d.style.width='max-content';d.style.height='auto';
+var r=d.getBoundingClientRect(),w=Math.max(r.width,d.scrollWidth),h=Math.max(r.height,d.scrollHeight,document.body?document.body.scrollHeight:0);
+d.style.width=sw;d.style.height=sh;return Math.ceil(w)+','+Math.ceil(h)})()`)
+}
+
+// PreferredSizeOf measures the size in CSS pixels of the first element
+// matching selector (e.g. a dialog's root), including overflowing content.
+func (ui *UltralightUI) PreferredSizeOf(selector string) (w, h int, err error) {
+	sel, _ := json.Marshal(selector)
+	return ui.measure(`(function(){var e=document.querySelector(` + string(sel) + `);if(!e)return'';
+var r=e.getBoundingClientRect();return Math.ceil(Math.max(r.width,e.scrollWidth))+','+Math.ceil(Math.max(r.height,e.scrollHeight))})()`)
+}
+
+// measure runs a script returning "w,h" and parses the result.
+func (ui *UltralightUI) measure(script string) (int, int, error) {
+	res, err := ui.EvalSync(script)
+	if err != nil {
+		return 0, 0, err
+	}
+	var w, h int
+	if _, err := fmt.Sscanf(res, "%d,%d", &w, &h); err != nil {
+		return 0, 0, fmt.Errorf("measure: element not found or invalid size %q", res)
+	}
+	return w, h, nil
+}
+
 // SurfaceSize returns the actual surface dimensions as reported by Ultralight.
 // On standard displays this matches (width, height). On HiDPI displays the
 // surface may be larger (e.g., 2x on macOS Retina).
 func (ui *UltralightUI) SurfaceSize() (int, int) {
-	if ui.closed {
+	if ui.closed.Load() {
 		return ui.width, ui.height
 	}
 	sw := int(ulViewGetSurfaceWidth(ui.viewID))
@@ -959,12 +2226,40 @@ func (ui *UltralightUI) getMouseScale() float64 {
 // For synchronously created views this always returns true.
 // For async views (NewFromFSAsync), it returns false until priming+loading is done.
 func (ui *UltralightUI) IsReady() bool {
-	if ui.closed {
+	if ui.closed.Load() {
 		return false
 	}
 	return ulViewIsReady(ui.viewID) != 0
 }
 
+// WaitReady blocks until the page is ready for Eval and Send (DOM ready and
+// helpers installed) or ctx is done, e.g. to push initial state right after
+// creating a view. It ticks the renderer and runs the view's update logic in
+// a loop, since readiness only advances on updates. Call it from the thread
+// that calls Update (the one Ultralight was initialized on), never
+// concurrently with Update. On cancellation it returns an error matching
+// both ErrNotReady and ctx.Err().
+func (ui *UltralightUI) WaitReady(ctx context.Context) error {
+	for {
+		if ui.closed.Load() {
+			return ErrClosed
+		}
+		if ui.scriptsReady() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%w: %w", ErrNotReady, err)
+		}
+		if err := ui.Update(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(renderOnceInterval):
+		}
+	}
+}
+
 // handleInputFocusMsg intercepts __inputFocus messages sent by common.js
 // when a text input gains or loses DOM focus. Returns true if the message
 // was consumed (caller should skip OnMessage).
@@ -979,30 +2274,73 @@ func (ui *UltralightUI) handleInputFocusMsg(msg string) bool {
 	if json.Unmarshal([]byte(msg), &data) != nil || data.Action != "__inputFocus" {
 		return false
 	}
+	changed := ui.inputFocused.Swap(data.Focused) != data.Focused
 	if data.Focused {
 		inputFocusViewID.Store(ui.viewID)
 	} else {
 		inputFocusViewID.CompareAndSwap(ui.viewID, -1)
 	}
+	if changed && ui.OnInputFocusChange != nil {
+		ui.OnInputFocusChange(data.Focused)
+	}
 	return true
 }
 
 // Close releases resources. Call when done (e.g. defer ui.Close()).
-// After Close, the UI must not be used.
+// After Close, the UI must not be used. Messages the page sent before Close
+// are delivered to OnMessage first (see Drain).
+//
+// Close may be called from OnMessage and the other callbacks, or while another
+// goroutine is in Update: the view is marked closed immediately and, if an
+// Update is in progress, destroyed when that Update returns, so it never
+// touches a freed view.
 func (ui *UltralightUI) Close() {
-	if ui.closed {
+	if !ui.closed.CompareAndSwap(false, true) {
 		return
 	}
-	ui.closed = true
+	// Deliver what the page already sent. Handlers see the view as closed, so
+	// a Close from OnMessage here returns at once. During an Update, its own
+	// poll loop delivers them instead.
+	if !ui.updating.Load() {
+		ui.pollMessages()
+	}
+	ui.unregisterName()
+	ui.unregisterLive()
 	inputFocusViewID.CompareAndSwap(ui.viewID, -1)
+	if ui.inputFocused.Swap(false) && ui.OnInputFocusChange != nil {
+		ui.OnInputFocusChange(false)
+	}
 	if getFocusedViewID() == ui.viewID {
 		setFocusedViewID(-1)
 	}
-	ulDestroyView(ui.viewID)
-	unregisterView()
-	if ui.texture != nil {
-		ui.texture.Deallocate()
-		ui.texture = nil
+	if ui.unlockPixels != nil {
+		ui.unlockPixels()
 	}
-	ui.pixels = nil
+	if ui.updating.Load() {
+		return // endUpdate destroys the view
+	}
+	ui.destroy()
+}
+
+// destroy releases the bridge view and textures, once.
+func (ui *UltralightUI) destroy() {
+	ui.destroyOnce.Do(func() {
+		ulDestroyView(ui.viewID)
+		unregisterView()
+		if ui.session != nil {
+			ui.session.release(ui)
+		}
+		if ui.texture != nil {
+			ui.texture.Deallocate()
+			ui.texture = nil
+		}
+		if ui.backTexture != nil {
+			ui.backTexture.Deallocate()
+			ui.backTexture = nil
+		}
+		ui.releaseFrames()
+		ui.pendingSends = nil
+		ui.preReady = nil
+		ui.downloads = nil
+	})
 }
@@ -1,7 +1,29 @@
 package ultralightui
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
+	"unsafe"
+
+	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func TestParseMessage_Empty(t *testing.T) {
@@ -111,6 +133,49 @@ func TestParseMessage_JSONBool(t *testing.T) {
 	}
 }
 
+func TestDecode(t *testing.T) {
+	type buy struct {
+		Type string `json:"type"`
+		Item string `json:"item"`
+	}
+	v, err := Decode[buy](` {"type":"buy","item":"sword"} `)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != (buy{"buy", "sword"}) {
+		t.Errorf("got %+v", v)
+	}
+	if _, err := Decode[buy]("hello world"); err == nil || !strings.Contains(err.Error(), "not JSON") {
+		t.Errorf("plain string: expected not-JSON error, got %v", err)
+	}
+	if _, err := Decode[buy](`[1,2]`); err == nil {
+		t.Error("array into struct: expected error")
+	}
+}
+
+func TestMessageRouter(t *testing.T) {
+	var got []string
+	r := NewRouter()
+	r.Handle("buy", func(raw json.RawMessage) { got = append(got, "buy "+string(raw)) })
+	r.Fallback(func(msg string) { got = append(got, "fallback "+msg) })
+	for _, msg := range []string{`{"type":"buy","item":"sword"}`, `{"type":"sell"}`, `{"type":3}`, `ping`} {
+		r.Dispatch(msg)
+	}
+	want := []string{`buy {"type":"buy","item":"sword"}`, `fallback {"type":"sell"}`, `fallback {"type":3}`, `fallback ping`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = nil
+	r = NewRouter().SetTypeKey("action")
+	r.Handle("move", func(raw json.RawMessage) { got = append(got, "move") })
+	r.Dispatch(`{"action":"move","type":"buy"}`)
+	r.Dispatch(`{"type":"move"}`) // no fallback: dropped
+	if !reflect.DeepEqual(got, []string{"move"}) {
+		t.Errorf("custom key: got %q", got)
+	}
+}
+
 func TestVkToChar_Letters(t *testing.T) {
 	tests := []struct {
 		vk   int32
@@ -209,6 +274,25 @@ func TestResolveOpts_Custom(t *testing.T) {
 	}
 }
 
+func TestResolveOpts_BridgePath(t *testing.T) {
+	lib := filepath.Join("opt", "game", "v3", bridgeLibName())
+	opts := &Options{BridgePath: lib}
+	baseDir, _ := resolveOpts(opts)
+	if want := filepath.Join("opt", "game", "v3"); baseDir != want {
+		t.Errorf("baseDir = %s, want %s", baseDir, want)
+	}
+	if got := bridgePath(opts, baseDir); got != lib {
+		t.Errorf("bridgePath = %s, want %s", got, lib)
+	}
+	opts.BaseDir = "sdk"
+	if baseDir, _ = resolveOpts(opts); baseDir != "sdk" {
+		t.Errorf("explicit BaseDir should win, got %s", baseDir)
+	}
+	if got, want := bridgePath(nil, "sdk"), filepath.Join("sdk", bridgeLibName()); got != want {
+		t.Errorf("default bridgePath = %s, want %s", got, want)
+	}
+}
+
 func TestErrClosed(t *testing.T) {
 	if ErrClosed == nil {
 		t.Fatal("ErrClosed should not be nil")
@@ -217,3 +301,2877 @@ func TestErrClosed(t *testing.T) {
 		t.Errorf("unexpected error message: %s", ErrClosed.Error())
 	}
 }
+
+func TestViewFlags(t *testing.T) {
+	if f := viewFlags(nil); f != 0 {
+		t.Errorf("nil opts: expected 0, got %#x", f)
+	}
+	if f := viewFlags(&Options{}); f != 0 {
+		t.Errorf("empty opts: expected 0, got %#x", f)
+	}
+	if f := viewFlags(&Options{DisableImages: true}); f&viewFlagDisableImages == 0 {
+		t.Errorf("DisableImages: expected flag %#x set, got %#x", viewFlagDisableImages, f)
+	}
+	if f := viewFlags(&Options{DisableJavaScript: true}); f != viewFlagDisableJavaScript {
+		t.Errorf("DisableJavaScript: expected flag %#x, got %#x", viewFlagDisableJavaScript, f)
+	}
+}
+
+func TestDetectMimeType(t *testing.T) {
+	// System MIME tables may report application/javascript or text/javascript;
+	// both are accepted by Ultralight as scripts.
+	if got := detectMimeType("ui/app.mjs"); !strings.Contains(got, "javascript") {
+		t.Errorf("detectMimeType(.mjs) = %q, want a javascript type", got)
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"ui/lib/module.wasm", "application/wasm"},
+		{"ui/fonts/Inter.woff2", "font/woff2"},
+		{"ui/index.html", "text/html"},
+		{"ui/style.CSS", "text/css"},
+		{"ui/LICENSE", ""},
+	}
+	for _, tt := range tests {
+		if got := detectMimeType(tt.path); got != tt.want {
+			t.Errorf("detectMimeType(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestRegisterFS_ModuleImport checks that an ES module imported by a page is
+// served from the path the import resolves to, with a script MIME type
+// (Ultralight refuses modules served with any other type).
+func TestRegisterFS_ModuleImport(t *testing.T) {
+	orig := ulVfsRegisterTyped
+	defer func() { ulVfsRegisterTyped = orig }()
+	resetVFSRegistry(t)
+	registered := map[string]string{}
+	ulVfsRegisterTyped = func(path string, data uintptr, size int64, mimeType string) int32 {
+		registered[path] = mimeType
+		return 0
+	}
+	files := fstest.MapFS{
+		"ui/index.html":    {Data: []byte(`<script type="module">import {hp} from './lib/state.mjs'; go.send(hp)</script>`)},
+		"ui/lib/state.mjs": {Data: []byte("export const hp = 100;")},
+	}
+	if err := registerFS(files, nil); err != nil {
+		t.Fatal(err)
+	}
+	page, err := url.Parse(vfsURL("ui/index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := strings.TrimPrefix(page.ResolveReference(&url.URL{Path: "./lib/state.mjs"}).Path, "/")
+	if mime, ok := registered[module]; !ok || !strings.Contains(mime, "javascript") {
+		t.Errorf("module %q registered with MIME %q (ok=%v), want a javascript type; registered %v", module, mime, ok, registered)
+	}
+	if registered["ui/index.html"] != "text/html" {
+		t.Errorf("page MIME = %q", registered["ui/index.html"])
+	}
+}
+
+func TestRegisterHTTPFS(t *testing.T) {
+	orig := ulVfsRegisterTyped
+	defer func() { ulVfsRegisterTyped = orig }()
+	resetVFSRegistry(t)
+	registered := map[string]string{}
+	ulVfsRegisterTyped = func(path string, data uintptr, size int64, mimeType string) int32 {
+		registered[path] = mimeType
+		return 0
+	}
+	files := fstest.MapFS{
+		"index.html":       {Data: []byte("<html></html>")},
+		"ui/app.js":        {Data: []byte("go.send(1)")},
+		"ui/fonts/a.woff2": {Data: []byte("wOF2")},
+		"ui/empty/.keep":   {Data: nil},
+	}
+	if err := registerHTTPFS(http.FS(files), "/", nil); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"index.html":       "text/html",
+		"ui/app.js":        detectMimeType("ui/app.js"),
+		"ui/fonts/a.woff2": "font/woff2",
+	}
+	if !reflect.DeepEqual(registered, want) {
+		t.Errorf("registered = %v, want %v", registered, want)
+	}
+	if err := registerHTTPFS(http.FS(files), "/missing", nil); err == nil {
+		t.Error("missing root should fail")
+	}
+	for _, main := range []string{"ui/index.html", "/ui/index.html", `ui\index.html`, "./ui/../ui/index.html"} {
+		if got := vfsURL(main); got != "file:///ui/index.html" {
+			t.Errorf("vfsURL(%q) = %q", main, got)
+		}
+	}
+}
+
+func TestVFSFilter(t *testing.T) {
+	orig := ulVfsRegisterTyped
+	defer func() { ulVfsRegisterTyped = orig }()
+	resetVFSRegistry(t)
+	var registered []string
+	ulVfsRegisterTyped = func(path string, data uintptr, size int64, mimeType string) int32 {
+		registered = append(registered, path)
+		return 0
+	}
+	files := fstest.MapFS{
+		"ui/app.js":     {Data: []byte("x")},
+		"ui/app.js.map": {Data: []byte("{}")},
+		"ui/README.md":  {Data: []byte("# ui")},
+	}
+	var asked []string
+	filter := func(p string) bool {
+		asked = append(asked, p)
+		return !strings.HasSuffix(p, ".map") && !strings.HasSuffix(p, ".md")
+	}
+	opts := &Options{VFSFilter: filter}
+	if err := registerFS(files, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := registerHTTPFS(http.FS(files), "/", opts); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ui/app.js"}; !reflect.DeepEqual(registered, want) { // identical the second time
+		t.Errorf("registered = %v, want %v", registered, want)
+	}
+	if len(asked) != 6 || asked[3] != "ui/README.md" {
+		t.Errorf("filter should get normalized VFS paths: %v", asked)
+	}
+	if vfsFilter(nil) != nil || vfsFilter(&Options{}) != nil {
+		t.Error("no filter should register everything")
+	}
+}
+
+func TestUnregisterFile(t *testing.T) {
+	orig := ulVfsUnregister
+	defer func() { ulVfsUnregister = orig }()
+	resetVFSRegistry(t)
+	ulVfsUnregister = nil
+	if err := UnregisterFile("ui/theme.css"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("without symbol: %v", err)
+	}
+	files := map[string]bool{"ui/theme.css": true}
+	ulVfsUnregister = func(path string) int32 {
+		if !files[path] {
+			return vfsNotRegistered
+		}
+		delete(files, path)
+		return 0
+	}
+	if err := UnregisterFile(`\ui\theme.css`); err != nil || len(files) != 0 {
+		t.Errorf("UnregisterFile = %v, files = %v", err, files)
+	}
+	if err := UnregisterFile("/ui/theme.css"); !errors.Is(err, ErrFileNotRegistered) {
+		t.Errorf("second UnregisterFile = %v, want ErrFileNotRegistered", err)
+	}
+}
+
+// resetVFSRegistry empties the Go-side VFS registry for the test.
+func resetVFSRegistry(t testing.TB) {
+	vfsMu.Lock()
+	saved := vfsFiles
+	vfsFiles = map[string]vfsEntry{}
+	vfsMu.Unlock()
+	t.Cleanup(func() {
+		vfsMu.Lock()
+		vfsFiles = saved
+		vfsMu.Unlock()
+	})
+}
+
+func TestRegisterFont(t *testing.T) {
+	origTyped, origUnreg := ulVfsRegisterTyped, ulVfsUnregister
+	t.Cleanup(func() { ulVfsRegisterTyped, ulVfsUnregister = origTyped, origUnreg })
+	resetVFSRegistry(t)
+	savedFonts, savedGen := vfsFonts, fontGen.Load()
+	vfsFonts = nil
+	t.Cleanup(func() { vfsFonts = savedFonts; fontGen.Store(savedGen) })
+	registered := map[string]string{}
+	ulVfsRegisterTyped = func(path string, data uintptr, size int64, mimeType string) int32 {
+		registered[path] = mimeType
+		return 0
+	}
+	ulVfsUnregister = func(path string) int32 {
+		if _, ok := registered[path]; !ok {
+			return vfsNotRegistered
+		}
+		delete(registered, path)
+		return 0
+	}
+	var evals []string
+	captureEvals(t, &evals)
+
+	if err := RegisterFont("Noto Sans", []byte("wOF2\x00\x01")); err != nil {
+		t.Fatal(err)
+	}
+	if registered["_fonts/Noto-Sans.woff2"] != "font/woff2" {
+		t.Errorf("registered %v", registered)
+	}
+	if err := RegisterFont("x\"}body{", []byte("wOF2")); err == nil {
+		t.Error("a family name that breaks out of the CSS string should be rejected")
+	}
+	if err := RegisterFont("Bad", []byte("<html>")); err == nil {
+		t.Error("non-font data should be rejected")
+	}
+
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+	ui.applyFonts()
+	ui.applyFonts() // nothing new
+	if len(evals) != 1 || !strings.Contains(evals[0], `font-family:\"Noto Sans\";src:url(\"file:///_fonts/Noto-Sans.woff2\") format(\"woff2\")`) {
+		t.Fatalf("evals = %v", evals)
+	}
+
+	// Re-registering a name replaces its font.
+	evals = nil
+	if err := RegisterFont("Noto Sans", []byte{0, 1, 0, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	ui.applyFonts()
+	if len(evals) != 1 || strings.Contains(evals[0], "woff2") || !strings.Contains(evals[0], `Noto-Sans.ttf\") format(\"truetype\")`) {
+		t.Fatalf("after replacing: evals = %v", evals)
+	}
+	if _, ok := registered["_fonts/Noto-Sans.woff2"]; ok {
+		t.Error("the replaced font's old file was left in the VFS")
+	}
+
+	// Unregistered fonts are left out of the next page.
+	if err := UnregisterFile("_fonts/Noto-Sans.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	evals = nil
+	ui.resetPageHelpers()
+	ui.applyFonts()
+	if last := evals[len(evals)-1]; strings.Contains(last, "@font-face") {
+		t.Errorf("unregistered font still declared: %s", last)
+	}
+}
+
+// requireSDK skips tb unless the bridge library is next to the package (or
+// the test binary), and loads it with Ultralight.
+func requireSDK(tb testing.TB) {
+	tb.Helper()
+	baseDir, _ := resolveOpts(nil)
+	if _, err := os.Stat(bridgePath(nil, baseDir)); err != nil {
+		tb.Skip("Ultralight SDK not available:", err)
+	}
+	if err := Preload(nil); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+// TestRegisterFont_ShapesGlyphs renders a run of "i" in a registered
+// monospace font and in an unknown family: if the page shapes with the
+// registered font, its text is wider than the proportional fallback's.
+// Needs the Ultralight SDK next to the package and a system TrueType font.
+func TestRegisterFont_ShapesGlyphs(t *testing.T) {
+	requireSDK(t)
+	var font []byte
+	for _, p := range []string{
+		"/usr/share/fonts/truetype/dejavu/DejaVuSansMono.ttf",
+		"/usr/share/fonts/TTF/DejaVuSansMono.ttf",
+		"/System/Library/Fonts/Supplemental/Courier New.ttf",
+		`C:\Windows\Fonts\cour.ttf`,
+	} {
+		if data, err := os.ReadFile(p); err == nil {
+			font = data
+			break
+		}
+	}
+	if font == nil {
+		t.Skip("no monospace TrueType font found")
+	}
+	savedFonts, savedGen := vfsFonts, fontGen.Load()
+	t.Cleanup(func() {
+		UnregisterFile("_fonts/UL-Test-Mono.ttf")
+		vfsFonts = savedFonts
+		fontGen.Store(savedGen)
+	})
+	if err := RegisterFont("UL Test Mono", font); err != nil {
+		t.Fatal(err)
+	}
+
+	// RenderOnce doesn't run Update, so the page declares the face itself
+	// from the URL RegisterFont serves it at.
+	inkWidth := func(family string) int {
+		t.Helper()
+		page := `<style>@font-face{font-family:"UL Test Mono";src:url("file:///_fonts/UL-Test-Mono.ttf") format("truetype")}` +
+			`body{margin:0;font:32px "` + family + `"}</style><span>iiiiiiiiii</span>`
+		img, err := RenderOnce(600, 60, []byte(page), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		right := 0
+		for y := 0; y < 60; y++ {
+			for x := 0; x < 600; x++ {
+				if img.RGBAAt(x, y).A > 0 && x+1 > right {
+					right = x + 1
+				}
+			}
+		}
+		return right
+	}
+	registered, fallback := inkWidth("UL Test Mono"), inkWidth("UL No Such Font")
+	if registered == 0 || fallback == 0 {
+		t.Fatalf("nothing rendered: %d, %d px", registered, fallback)
+	}
+	if registered <= fallback {
+		t.Errorf("registered font is %d px wide, fallback %d px: the font wasn't used", registered, fallback)
+	}
+}
+
+func TestVFSRegistry(t *testing.T) {
+	origTyped, origUnreg, origClear := ulVfsRegisterTyped, ulVfsUnregister, ulVfsClear
+	t.Cleanup(func() { ulVfsRegisterTyped, ulVfsUnregister, ulVfsClear = origTyped, origUnreg, origClear })
+	resetVFSRegistry(t)
+	var calls []string
+	ulVfsRegisterTyped = func(path string, data uintptr, size int64, mimeType string) int32 {
+		calls = append(calls, path)
+		return 0
+	}
+	ulVfsUnregister = func(path string) int32 { return 0 }
+	ulVfsClear = func() {}
+
+	if files := VFSFiles(); len(files) != 0 {
+		t.Errorf("empty registry: %v", files)
+	}
+	// Re-registering the same path twice: identical content is skipped,
+	// different content (or type) replaces it in the bridge.
+	for _, data := range []string{"body{}", "body{}", "body{color:red}"} {
+		if err := RegisterFile("ui/theme.css", []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RegisterFileWithType("ui/theme.css", []byte("body{color:red}"), "text/css"); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 3 {
+		t.Errorf("bridge registrations = %d, want 3 (identical content skipped)", len(calls))
+	}
+	if replaced, _ := registerVFS("ui/theme.css", []byte("body{}"), "text/css"); !replaced {
+		t.Error("different content should report a replacement")
+	}
+	if replaced, _ := registerVFS("ui/theme.css", []byte("body{}"), ""); replaced {
+		t.Error("a type change alone is not a content replacement")
+	}
+
+	RegisterFile(`\ui\index.html`, []byte("<html>"))
+	if files := VFSFiles(); !reflect.DeepEqual(files, []string{"ui/index.html", "ui/theme.css"}) {
+		t.Errorf("VFSFiles = %v", files)
+	}
+	UnregisterFile("ui/theme.css")
+	if files := VFSFiles(); !reflect.DeepEqual(files, []string{"ui/index.html"}) {
+		t.Errorf("after UnregisterFile: %v", files)
+	}
+	ClearFiles()
+	calls = nil
+	RegisterFile("ui/index.html", []byte("<html>"))
+	if len(VFSFiles()) != 1 || len(calls) != 1 {
+		t.Error("ClearFiles should empty the registry, so files register again")
+	}
+}
+
+// BenchmarkRegisterFS registers a 50-file FS as NewFromFS does for each of
+// 5 views: only view1's walk reaches the bridge, views 2-5 find every file
+// already registered with the same content.
+func BenchmarkRegisterFS(b *testing.B) {
+	orig := ulVfsRegisterTyped
+	defer func() { ulVfsRegisterTyped = orig }()
+	resetVFSRegistry(b)
+	var calls int
+	ulVfsRegisterTyped = func(path string, data uintptr, size int64, mimeType string) int32 {
+		calls++
+		_ = make([]byte, size) // the bridge mallocs and copies each file
+		return 0
+	}
+	files := fstest.MapFS{}
+	for i := 0; i < 50; i++ {
+		files[fmt.Sprintf("ui/part%02d.js", i)] = &fstest.MapFile{Data: bytes.Repeat([]byte{byte(i)}, 32<<10)}
+	}
+	for view := 1; view <= 5; view++ {
+		b.Run(fmt.Sprintf("view%d", view), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				vfsMu.Lock()
+				vfsFiles = map[string]vfsEntry{}
+				vfsMu.Unlock()
+				for range view - 1 {
+					registerFS(files, nil)
+				}
+				calls = 0
+				b.StartTimer()
+				if err := registerFS(files, nil); err != nil {
+					b.Fatal(err)
+				}
+				want := 0
+				if view == 1 {
+					want = len(files)
+				}
+				if calls != want {
+					b.Fatalf("%d files reached the bridge, want %d", calls, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterFS_ReplaceWarning(t *testing.T) {
+	orig := ulVfsRegisterTyped
+	defer func() { ulVfsRegisterTyped = orig }()
+	resetVFSRegistry(t)
+	ulVfsRegisterTyped = func(path string, data uintptr, size int64, mimeType string) int32 { return 0 }
+
+	var out bytes.Buffer
+	opts := &Options{Logger: slog.New(slog.NewTextHandler(&out, nil))}
+	registerFS(fstest.MapFS{"ui/app.js": {Data: []byte("v1")}}, opts)
+	registerFS(fstest.MapFS{"ui/app.js": {Data: []byte("v1")}}, opts) // identical
+	if out.Len() != 0 {
+		t.Fatalf("identical content logged: %s", out.String())
+	}
+	registerFS(fstest.MapFS{"ui/app.js": {Data: []byte("v2")}}, opts)
+	if !strings.Contains(out.String(), "replaced") || !strings.Contains(out.String(), "path=ui/app.js") {
+		t.Errorf("replacement not sent to Options.Logger: %q", out.String())
+	}
+}
+
+func TestVFSAsFS(t *testing.T) {
+	origTyped, origUnreg := ulVfsRegisterTyped, ulVfsUnregister
+	t.Cleanup(func() { ulVfsRegisterTyped, ulVfsUnregister = origTyped, origUnreg })
+	resetVFSRegistry(t)
+	t.Cleanup(func() { SetKeepVFSContent(false) })
+	ulVfsRegisterTyped = func(path string, data uintptr, size int64, mimeType string) int32 { return 0 }
+	ulVfsUnregister = func(path string) int32 { return 0 }
+
+	RegisterFile("ui/early.css", []byte("a{}")) // before keeping content
+	SetKeepVFSContent(true)
+	files := fstest.MapFS{
+		"ui/index.html":   {Data: []byte("<html></html>")},
+		"ui/js/app.js":    {Data: []byte("go.send(1)")},
+		"ui/js/vendor.js": {Data: []byte("lib()")},
+	}
+	if err := registerFS(files, nil); err != nil {
+		t.Fatal(err)
+	}
+	vfs := VFSAsFS()
+	if data, err := fs.ReadFile(vfs, "ui/index.html"); err != nil || string(data) != "<html></html>" {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+	if _, err := fs.ReadFile(vfs, "ui/early.css"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("file registered before SetKeepVFSContent: %v", err)
+	}
+	UnregisterFile("ui/js/vendor.js")
+	if err := fstest.TestFS(vfs, "ui/index.html", "ui/js/app.js"); err != nil {
+		t.Error(err)
+	}
+	if _, err := fs.Stat(vfs, "ui/js/vendor.js"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("unregistered file still readable: %v", err)
+	}
+	SetKeepVFSContent(false)
+	if entries, _ := fs.ReadDir(vfs, "."); len(entries) != 0 {
+		t.Errorf("disabling should drop the kept content: %v", entries)
+	}
+}
+
+func TestQualityMode_Adaptive(t *testing.T) {
+	ui := &UltralightUI{}
+	ui.SetQualityMode(QualityAdaptive)
+	ui.SetFrameBudget(2 * time.Millisecond)
+	if ui.IsThrottled() || ui.shouldSkipFrame(1) {
+		t.Fatal("adaptive view should start unthrottled")
+	}
+	for i := 0; i < 50; i++ {
+		ui.recordFrameCost(10 * time.Millisecond)
+	}
+	if !ui.IsThrottled() {
+		t.Fatal("expected throttling above budget")
+	}
+	if !ui.shouldSkipFrame(1) || ui.shouldSkipFrame(2) {
+		t.Error("throttled view should skip odd frames only")
+	}
+	for i := 0; i < 50; i++ {
+		ui.recordFrameCost(100 * time.Microsecond)
+	}
+	if ui.IsThrottled() {
+		t.Fatal("expected throttling to lift well below budget")
+	}
+}
+
+func TestQualityMode_Low(t *testing.T) {
+	ui := &UltralightUI{}
+	ui.SetQualityMode(QualityLow)
+	if !ui.IsThrottled() {
+		t.Fatal("QualityLow should always be throttled")
+	}
+	ui.SetQualityMode(QualityFull)
+	if ui.IsThrottled() || ui.shouldSkipFrame(1) {
+		t.Fatal("QualityFull should never skip frames")
+	}
+}
+
+func TestSetUpdateInterval(t *testing.T) {
+	ui := &UltralightUI{}
+	t0 := time.Unix(1000, 0)
+	if !ui.refreshDue(t0) {
+		t.Fatal("no interval: every Update refreshes")
+	}
+
+	// 120 TPS with a 30 FPS cap: one refresh every 4 ticks on average.
+	ui.SetUpdateInterval(time.Second / 30)
+	tick := time.Second / 120
+	refreshes := 0
+	for i := 0; i < 120; i++ {
+		jitter := time.Duration(i%3) * time.Millisecond / 2
+		if ui.refreshDue(t0.Add(time.Duration(i)*tick + jitter)) {
+			refreshes++
+		}
+	}
+	if refreshes < 29 || refreshes > 31 {
+		t.Errorf("refreshes in 1s at 120 TPS = %d, want ~30", refreshes)
+	}
+
+	// After a stall the schedule restarts instead of refreshing every call.
+	later := t0.Add(10 * time.Second)
+	if !ui.refreshDue(later) || ui.refreshDue(later.Add(tick)) {
+		t.Error("after a stall only the first Update should refresh")
+	}
+
+	ui.SetUpdateInterval(0)
+	if !ui.refreshDue(later.Add(tick)) {
+		t.Error("interval 0 should refresh every Update")
+	}
+}
+
+func TestBuildReceiveBatch(t *testing.T) {
+	js := buildReceiveBatch([][]byte{[]byte(`{"hp":80}`), []byte(`[1,2]`)})
+	first := strings.Index(js, `r({"hp":80});`)
+	second := strings.Index(js, `r([1,2]);`)
+	if first < 0 || second < 0 {
+		t.Fatalf("missing receive calls in %q", js)
+	}
+	if first > second {
+		t.Errorf("payloads out of order in %q", js)
+	}
+	if !strings.HasPrefix(js, "(function(){") || !strings.HasSuffix(js, "})();") {
+		t.Errorf("batch should be a single IIFE, got %q", js)
+	}
+}
+
+func TestHandleDownloadMsg(t *testing.T) {
+	var gotName string
+	var gotData []byte
+	calls := 0
+	ui := &UltralightUI{OnDownload: func(name string, data []byte) {
+		calls++
+		gotName, gotData = name, data
+	}}
+	// "hello world" split into two independently decodable base64 chunks
+	msgs := []string{
+		`{"action":"__download","id":1,"name":"../reports/out.txt","data":"aGVsbG8g","last":false}`,
+		`{"action":"__download","id":1,"name":"../reports/out.txt","data":"d29ybGQ=","last":true}`,
+	}
+	for _, m := range msgs {
+		if !ui.handleDownloadMsg(m) {
+			t.Fatalf("message not consumed: %s", m)
+		}
+	}
+	if calls != 1 || gotName != "out.txt" || string(gotData) != "hello world" {
+		t.Fatalf("got %d calls, name=%q data=%q", calls, gotName, gotData)
+	}
+	if ui.handleDownloadMsg(`{"action":"click"}`) {
+		t.Error("regular messages must not be consumed")
+	}
+	ui.handleDownloadMsg(`{"action":"__download","id":2,"name":"x","error":"failed","last":true}`)
+	if calls != 1 {
+		t.Error("failed downloads must not be delivered")
+	}
+	if len(ui.downloads) != 0 {
+		t.Errorf("expected no pending downloads, got %d", len(ui.downloads))
+	}
+}
+
+func TestSanitizeDownloadName(t *testing.T) {
+	tests := map[string]string{
+		"report.csv":         "report.csv",
+		"../../etc/passwd":   "passwd",
+		`C:\saves\slot1.sav`: "slot1.sav",
+		"":                   "download",
+		"..":                 "download",
+	}
+	for in, want := range tests {
+		if got := sanitizeDownloadName(in); got != want {
+			t.Errorf("sanitizeDownloadName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMapKey_Layouts(t *testing.T) {
+	defer SetKeyboardLayout(nil)
+	tests := []struct {
+		layout KeyboardLayout
+		key    ebiten.Key
+		vk     int32
+		char   string
+	}{
+		{LayoutUS, ebiten.KeyZ, 0x5A, "z"},
+		{LayoutAZERTY, ebiten.KeyW, 0x5A, "z"}, // Z is where US has W
+		{LayoutAZERTY, ebiten.KeyQ, 0x41, "a"},
+		{LayoutAZERTY, ebiten.KeyS, 0x53, "s"}, // unchanged keys fall back to US
+		{LayoutQWERTZ, ebiten.KeyY, 0x5A, "z"},
+		{LayoutQWERTZ, ebiten.KeyF1, 0x70, ""},
+	}
+	for _, tt := range tests {
+		SetKeyboardLayout(tt.layout)
+		vk, char := mapKey(tt.key)
+		if vk != tt.vk || char != tt.char {
+			t.Errorf("mapKey(%v) = 0x%X %q, want 0x%X %q", tt.key, vk, char, tt.vk, tt.char)
+		}
+	}
+}
+
+func TestEditShortcut(t *testing.T) {
+	tests := []struct {
+		vk          int32
+		alt, shift  bool
+		wantContain string
+	}{
+		{0x5A, false, false, "__ulUndo"},
+		{0x5A, false, true, "__ulRedo"},
+		{0x59, false, false, "__ulRedo"},
+		{0x41, false, false, "__ulSelectAll"},
+		{0x41, true, false, ""}, // AltGr+A types a character (e.g. Polish "ą")
+		{0x45, false, false, ""},
+	}
+	for _, tt := range tests {
+		got := editShortcut(tt.vk, tt.alt, tt.shift)
+		if tt.wantContain == "" && got != "" || !strings.Contains(got, tt.wantContain) {
+			t.Errorf("editShortcut(0x%X, alt=%v, shift=%v) = %q, want %q", tt.vk, tt.alt, tt.shift, got, tt.wantContain)
+		}
+	}
+}
+
+// captureEvals replaces ulViewEvalJS with one appending each script to *evals.
+func captureEvals(t testing.TB, evals *[]string) {
+	orig := ulViewEvalJS
+	t.Cleanup(func() { ulViewEvalJS = orig })
+	ulViewEvalJS = func(viewID int32, js string) { *evals = append(*evals, js) }
+}
+
+// fakeEvalSync replaces the bridge EvalSync functions: each script evaluates
+// to the result and status eval returns for it.
+func fakeEvalSync(t testing.TB, eval func(js string) (result string, status int32)) {
+	origSync, origLen, origCopy := ulViewEvalSync, ulEvalResultLen, ulEvalResultCopy
+	t.Cleanup(func() { ulViewEvalSync, ulEvalResultLen, ulEvalResultCopy = origSync, origLen, origCopy })
+	var result string
+	ulViewEvalSync = func(viewID int32, js string) int32 {
+		var status int32
+		result, status = eval(js)
+		return status
+	}
+	ulEvalResultLen = func() int32 { return int32(len(result)) }
+	ulEvalResultCopy = func(buf *byte, bufSize int32) int32 {
+		dst := unsafe.Slice(buf, bufSize)
+		return int32(copy(dst[:bufSize-1], result))
+	}
+}
+
+// fakeMessageQueue replaces the bridge message functions with an in-memory queue.
+func fakeMessageQueue(t *testing.T, msgs ...string) {
+	origLen, origGet := ulViewGetMessageLen, ulViewGetMessage
+	t.Cleanup(func() { ulViewGetMessageLen, ulViewGetMessage = origLen, origGet })
+	ulViewGetMessageLen = func(viewID int32) int32 {
+		if len(msgs) == 0 {
+			return -1
+		}
+		return int32(len(msgs[0]))
+	}
+	ulViewGetMessage = func(viewID int32, buf uintptr, bufSize int32) int32 {
+		if len(msgs) == 0 {
+			return 0
+		}
+		dst := unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&buf))), bufSize)
+		n := copy(dst[:bufSize-1], msgs[0])
+		dst[n] = 0
+		msgs = msgs[1:]
+		return int32(n)
+	}
+}
+
+func TestPollMessage_LargePayloads(t *testing.T) {
+	small := `{"inventory":"` + strings.Repeat("x", 10*1024) + `"}`
+	large := `{"inventory":"` + strings.Repeat("y", 100*1024) + `"}`
+	fakeMessageQueue(t, small, large)
+	for _, want := range []string{small, large} {
+		got, ok := pollMessage(0)
+		if !ok {
+			t.Fatalf("expected a %d-byte message", len(want))
+		}
+		if got != want {
+			t.Fatalf("message truncated: got %d bytes, want %d", len(got), len(want))
+		}
+		if _, err := ParseMessage(got); err != nil {
+			t.Fatalf("ParseMessage: %v", err)
+		}
+	}
+	if _, ok := pollMessage(0); ok {
+		t.Error("expected empty queue")
+	}
+}
+
+func TestApplyOpts_Focused(t *testing.T) {
+	defer ClearFocus()
+	a := &UltralightUI{view: view{viewID: 1}}
+	b := &UltralightUI{view: view{viewID: 2}}
+	a.applyOpts(&Options{Focused: true})
+	b.applyOpts(&Options{Focused: true})
+	if got := getFocusedViewID(); got != 2 {
+		t.Errorf("last view created with Focused should win, focused=%d", got)
+	}
+	(&UltralightUI{view: view{viewID: 3}}).applyOpts(nil)
+	if got := getFocusedViewID(); got != 2 {
+		t.Errorf("view without Focused must not take focus, focused=%d", got)
+	}
+}
+
+func TestDroppedFileInfos(t *testing.T) {
+	files := fstest.MapFS{
+		"photo.png":    {Data: make([]byte, 42)},
+		"notes.txt":    {Data: []byte("hi")},
+		"dir/skip.txt": {Data: []byte("x")},
+	}
+	infos := droppedFileInfos(files)
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 root files, got %+v", infos)
+	}
+	// fs.ReadDir returns entries sorted by name
+	if infos[0].Name != "notes.txt" || infos[1].Name != "photo.png" || infos[1].Size != 42 || infos[1].Type != "image/png" {
+		t.Errorf("unexpected infos: %+v", infos)
+	}
+	js := buildDropScript(infos, 10, 20)
+	if !strings.Contains(js, `"name":"photo.png"`) || !strings.HasSuffix(js, ",10,20);") {
+		t.Errorf("unexpected drop script: %s", js)
+	}
+}
+
+func TestHandleOverflowMsg(t *testing.T) {
+	var h, v bool
+	calls := 0
+	ui := &UltralightUI{OnOverflow: func(horizontal, vertical bool) {
+		calls++
+		h, v = horizontal, vertical
+	}}
+	if !ui.handleOverflowMsg(`{"action":"__overflow","horizontal":false,"vertical":true}`) {
+		t.Fatal("overflow message not consumed")
+	}
+	if calls != 1 || h || !v {
+		t.Errorf("got calls=%d h=%v v=%v", calls, h, v)
+	}
+	if ui.handleOverflowMsg(`{"action":"scroll"}`) {
+		t.Error("regular messages must not be consumed")
+	}
+}
+
+func TestZoomClamp(t *testing.T) {
+	ui := &UltralightUI{}
+	if ui.GetZoom() != 1 {
+		t.Fatalf("default zoom should be 1, got %v", ui.GetZoom())
+	}
+	tests := map[float64]float64{1.25: 1.25, 0.1: MinZoom, 10: MaxZoom}
+	for in, want := range tests {
+		ui.SetZoom(in)
+		if got := ui.GetZoom(); got != want {
+			t.Errorf("SetZoom(%v): GetZoom() = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestTextDirectionAndLocale(t *testing.T) {
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{}
+	ui.SetTextDirection("RTL")
+	ui.SetTextDirection("sideways") // ignored
+	ui.SetLocale("ar")
+	if ui.TextDirection() != "rtl" || ui.Locale() != "ar" || len(evals) != 0 {
+		t.Fatalf("before DOM ready: dir %q, lang %q, evals %v", ui.TextDirection(), ui.Locale(), evals)
+	}
+	ui.domReady, ui.goHelperInjected = true, true
+	ui.applyLocale()
+	if len(evals) != 1 || !strings.HasSuffix(evals[0], `("rtl","ar")`) {
+		t.Fatalf("evals = %v", evals)
+	}
+	ui.resetPageHelpers()
+	if ui.localeApplied {
+		t.Error("a new page should get dir and lang again")
+	}
+	evals = nil
+	ui.SetTextDirection("")
+	if len(evals) != 1 || !strings.HasSuffix(evals[0], `("","ar")`) {
+		t.Errorf("clearing the direction: evals = %v", evals)
+	}
+}
+
+// TestLocaleKeepsPageAttributes runs the locale script on a fake
+// <html lang="de" dir="rtl"> under node, when available.
+func TestLocaleKeepsPageAttributes(t *testing.T) {
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+	ui.SetTextDirection("ltr")
+	ui.SetTextDirection("")
+	other := &UltralightUI{domReady: true, goHelperInjected: true}
+	other.SetLocale("en")
+	if len(evals) != 3 || !strings.HasSuffix(evals[0], `("ltr",null)`) ||
+		!strings.HasSuffix(evals[1], `("",null)`) || !strings.HasSuffix(evals[2], `(null,"en")`) {
+		t.Fatalf("evals = %v", evals)
+	}
+
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node not installed")
+	}
+	page := `var attrs={lang:'de',dir:'rtl'};var document={documentElement:{
+hasAttribute:function(a){return a in attrs},getAttribute:function(a){return attrs[a]},
+setAttribute:function(a,v){attrs[a]=String(v)},removeAttribute:function(a){delete attrs[a]}}};
+function dump(){return attrs.lang+'|'+attrs.dir}`
+	script := page + "\nvar out=[];" +
+		evals[0] + ";out.push(dump());" + // SetTextDirection("ltr")
+		evals[1] + ";out.push(dump());" + // SetTextDirection("")
+		evals[2] + ";out.push(dump());" + // SetLocale("en")
+		"console.log(out.join(' '))"
+	got, err := exec.Command(node, "-e", script).CombinedOutput()
+	if err != nil {
+		t.Fatalf("node: %v\n%s", err, got)
+	}
+	if want := "de|ltr de|rtl en|rtl"; strings.TrimSpace(string(got)) != want {
+		t.Errorf("lang|dir after each call = %q, want %q", strings.TrimSpace(string(got)), want)
+	}
+}
+
+func TestSetModifierState(t *testing.T) {
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{}
+	ui.SetModifierState(true, false, false, false) // before DOM ready: kept
+	if len(evals) != 0 {
+		t.Fatalf("evals before DOM ready: %v", evals)
+	}
+	ui.domReady, ui.goHelperInjected = true, true
+	ui.applyModifiers()
+	ui.SetModifierState(true, false, false, false) // unchanged
+	ui.SetModifierState(true, true, false, false)
+	if len(evals) != 2 ||
+		!strings.HasSuffix(evals[0], `({shift:true,ctrl:false,alt:false,meta:false})`) ||
+		!strings.HasSuffix(evals[1], `({shift:true,ctrl:true,alt:false,meta:false})`) {
+		t.Errorf("evals = %v", evals)
+	}
+	ui.resetPageHelpers()
+	if ui.modsApplied {
+		t.Error("a new page should get the modifier state again")
+	}
+}
+
+func TestUpdate_Concurrent(t *testing.T) {
+	ui := &UltralightUI{}
+	ui.updating.Store(true) // simulate an Update in progress on another goroutine
+	if err := ui.Update(); err != ErrConcurrentUpdate {
+		t.Errorf("Update: expected ErrConcurrentUpdate, got %v", err)
+	}
+	if err := ui.UpdateNoTick(); err != ErrConcurrentUpdate {
+		t.Errorf("UpdateNoTick: expected ErrConcurrentUpdate, got %v", err)
+	}
+}
+
+func TestViewByName(t *testing.T) {
+	a := &UltralightUI{view: view{viewID: 1}}
+	b := &UltralightUI{view: view{viewID: 2}}
+	a.SetName("inventory")
+	defer a.SetName("")
+	if ViewByName("inventory") != a {
+		t.Fatal("expected view a")
+	}
+	b.SetName("inventory") // names are unique: moves to b
+	defer b.SetName("")
+	if ViewByName("inventory") != b || a.Name() != "" {
+		t.Fatalf("name should move to b (a.Name()=%q)", a.Name())
+	}
+	b.closed.Store(true)
+	if ViewByName("inventory") != nil {
+		t.Error("closed views must not be returned")
+	}
+	if ViewByName("missing") != nil {
+		t.Error("expected nil for unknown name")
+	}
+}
+
+func TestClose_DuringUpdate(t *testing.T) {
+	orig := ulDestroyView
+	defer func() { ulDestroyView = orig }()
+	destroyed := 0
+	ulDestroyView = func(viewID int32) { destroyed++ }
+
+	ui := &UltralightUI{view: view{viewID: 7}}
+	if ok, err := ui.beginUpdate(); !ok || err != nil {
+		t.Fatalf("beginUpdate = %v, %v", ok, err)
+	}
+	ui.Close() // e.g. from an OnMessage callback or another goroutine
+	if destroyed != 0 {
+		t.Fatal("view destroyed while an Update was in progress")
+	}
+	if ok, _ := ui.beginUpdate(); ok {
+		t.Fatal("closed view should not start a new Update")
+	}
+	ui.endUpdate()
+	ui.Close()
+	if destroyed != 1 {
+		t.Fatalf("expected exactly one destroy, got %d", destroyed)
+	}
+}
+
+func TestDiffFields(t *testing.T) {
+	raw := func(m map[string]interface{}) map[string]json.RawMessage {
+		b, _ := json.Marshal(m)
+		var out map[string]json.RawMessage
+		json.Unmarshal(b, &out)
+		return out
+	}
+	prev := raw(map[string]interface{}{"hp": 80, "mana": 40, "buffs": []string{"haste"}, "target": "orc"})
+	next := raw(map[string]interface{}{"hp": 75, "mana": 40, "buffs": []string{"haste"}, "gold": 3})
+	patch := diffFields(prev, next)
+	got, _ := json.Marshal(patch)
+	if want := `{"gold":3,"hp":75,"target":null}`; string(got) != want {
+		t.Errorf("patch = %s, want %s", got, want)
+	}
+	if p := diffFields(next, next); len(p) != 0 {
+		t.Errorf("identical state should produce an empty patch, got %v", p)
+	}
+	if p := diffFields(nil, next); len(p) != len(next) {
+		t.Errorf("first diff should include every field, got %v", p)
+	}
+}
+
+func TestEvalSync_Status(t *testing.T) {
+	var status int32
+	result := "640,480"
+	fakeEvalSync(t, func(js string) (string, int32) { return result, status })
+
+	ui := &UltralightUI{}
+	if w, h, err := ui.PreferredSize(); err != nil || w != 640 || h != 480 {
+		t.Fatalf("PreferredSize = %d, %d, %v", w, h, err)
+	}
+	status, result = 1, "ReferenceError: foo is not defined"
+	if _, err := ui.EvalSync("foo"); err == nil || !strings.Contains(err.Error(), "ReferenceError") {
+		t.Errorf("expected exception error, got %v", err)
+	}
+	status = -2
+	if _, err := ui.EvalSync("1"); err != ErrNotReady {
+		t.Errorf("expected ErrNotReady, got %v", err)
+	}
+}
+
+func TestEvalSync_KeepsPreReadyQueue(t *testing.T) {
+	fakeEvalSync(t, func(js string) (string, int32) { return "", -2 })
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{domReady: true} // helpers not injected yet
+	ui.Eval("go.receive(1)")
+	ui.EvalSync("1")
+	if len(evals) != 0 || len(ui.preReady) != 1 {
+		t.Fatalf("queued script ran before the helpers: evals=%q queued=%d", evals, len(ui.preReady))
+	}
+	ui.goHelperInjected = true
+	ui.EvalSync("1")
+	if len(evals) != 1 || evals[0] != "go.receive(1)" || len(ui.preReady) != 0 {
+		t.Fatalf("queued script not flushed once ready: evals=%q", evals)
+	}
+}
+
+func TestWaitForPaint(t *testing.T) {
+	origTick, origReady, origCopy := ulTick, ulViewIsReady, ulViewCopyPixelsRGBA
+	defer func() { ulTick, ulViewIsReady, ulViewCopyPixelsRGBA = origTick, origReady, origCopy }()
+	ticks := 0
+	ulTick = func() { ticks++ }
+	ulViewIsReady = func(viewID int32) int32 {
+		if ticks < 3 {
+			return 0
+		}
+		return 1
+	}
+	ui := &UltralightUI{view: view{pixels: make([]byte, 4)}}
+	// Paints on ticks 3 and 4, then settles.
+	ulViewCopyPixelsRGBA = func(viewID int32, dest uintptr, destSize int32) int32 {
+		if ticks > 4 {
+			return 0
+		}
+		ui.pixels[0] = byte(ticks)
+		return 1
+	}
+
+	if !ui.waitForPaint(time.Second) {
+		t.Fatal("expected a paint")
+	}
+	if ticks != 5 || ui.pixels[0] != 4 {
+		t.Errorf("ticks = %d, pixel = %d; want 5, 4", ticks, ui.pixels[0])
+	}
+
+	ulViewIsReady = func(viewID int32) int32 { return 0 }
+	if ui.waitForPaint(20 * time.Millisecond) {
+		t.Error("expected timeout while not ready")
+	}
+}
+
+func TestDispatchMessage_Default(t *testing.T) {
+	defer SetDefaultMessageHandler(nil)
+	ui := &UltralightUI{strictMessaging: true}
+	var from *UltralightUI
+	var got string
+	SetDefaultMessageHandler(func(v *UltralightUI, msg string) { from, got = v, msg })
+	ui.dispatchMessage("hello")
+	if from != ui || got != "hello" {
+		t.Errorf("default handler got (%p, %q)", from, got)
+	}
+
+	own := ""
+	ui.OnMessage = func(msg string) { own = msg }
+	got = ""
+	ui.dispatchMessage("mine")
+	if own != "mine" || got != "" {
+		t.Errorf("OnMessage should take precedence: own=%q default=%q", own, got)
+	}
+}
+
+func TestDispatchMessage_StrictDebugPanics(t *testing.T) {
+	ui := &UltralightUI{strictMessaging: true, debug: true}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unhandled message")
+		}
+	}()
+	ui.dispatchMessage("lost")
+}
+
+func TestViewCopyFrame_DoubleBuffer(t *testing.T) {
+	orig := ulViewCopyPixelsRGBA
+	defer func() { ulViewCopyPixelsRGBA = orig }()
+	frame := byte(0)
+	v := newView(0, 1, 1)
+	v.setDoubleBuffer(true)
+	ulViewCopyPixelsRGBA = func(viewID int32, dest uintptr, destSize int32) int32 {
+		frame++
+		v.back[0] = frame // dest is the back buffer
+		return 1
+	}
+
+	if v.hasFrame {
+		t.Fatal("new view should have no frame")
+	}
+	v.copyFrame()
+	first := v.pixels
+	v.copyFrame()
+	if first[0] != 1 || v.pixels[0] != 2 || !v.hasFrame {
+		t.Errorf("first = %d, pixels = %d; want 1, 2", first[0], v.pixels[0])
+	}
+
+	ulViewCopyPixelsRGBA = func(viewID int32, dest uintptr, destSize int32) int32 { return 0 }
+	if v.copyFrame() || v.pixels[0] != 2 {
+		t.Error("unchanged surface should keep the current frame")
+	}
+}
+
+// TestViewCopyFrame_PaddedSurface copies an odd-width view from a surface
+// whose rows are padded to 64 bytes. The fake converts like
+// ul_view_copy_pixels_rgba, which walks the surface stride in C; the Go frame
+// and dirtyPixels must stay packed at width*4 whatever the stride.
+func TestViewCopyFrame_PaddedSurface(t *testing.T) {
+	origFull, origDirty, origLast := ulViewCopyPixelsRGBA, ulViewCopyPixelsDirty, ulViewGetLastDirty
+	defer func() { ulViewCopyPixelsRGBA, ulViewCopyPixelsDirty, ulViewGetLastDirty = origFull, origDirty, origLast }()
+	const w, h = 801, 3
+	stride := (w*4 + 63) &^ 63
+	bgra := make([]byte, stride*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			copy(bgra[y*stride+x*4:], []byte{byte(x), byte(x >> 8), byte(y), 255})
+		}
+	}
+	var dirty image.Rectangle
+	convert := func(dst []byte) int32 {
+		if len(dst) < w*h*4 {
+			return 0
+		}
+		for y := dirty.Min.Y; y < dirty.Max.Y; y++ {
+			for x := dirty.Min.X; x < dirty.Max.X; x++ {
+				s, d := y*stride+x*4, (y*w+x)*4
+				dst[d], dst[d+1], dst[d+2], dst[d+3] = bgra[s+2], bgra[s+1], bgra[s], bgra[s+3]
+			}
+		}
+		return 1
+	}
+	var frame []byte
+	ulViewCopyPixelsRGBA = func(viewID int32, dest uintptr, destSize int32) int32 { return convert(frame) }
+	ulViewCopyPixelsDirty = ulViewCopyPixelsRGBA
+	ulViewGetLastDirty = func(viewID int32, out *int32) {
+		copy(unsafe.Slice(out, 4), []int32{int32(dirty.Min.X), int32(dirty.Min.Y), int32(dirty.Max.X), int32(dirty.Max.Y)})
+	}
+
+	v := newView(0, w, h)
+	frame = v.pixels
+	dirty = v.bounds()
+	if !v.copyFrame() || len(v.pixels) != w*h*4 {
+		t.Fatalf("frame is %d bytes, want %d", len(v.pixels), w*h*4)
+	}
+	for _, p := range []image.Point{{0, 0}, {800, 0}, {0, 1}, {800, 2}} {
+		d := (p.Y*w + p.X) * 4
+		if want := []byte{byte(p.Y), byte(p.X >> 8), byte(p.X), 255}; !bytes.Equal(v.pixels[d:d+4], want) {
+			t.Errorf("pixel %v = %v, want %v", p, v.pixels[d:d+4], want)
+		}
+	}
+	// A dirty rect on the last column: rows are w*4 apart in the frame.
+	bgra[1*stride+800*4+2] = 99
+	dirty = image.Rect(799, 1, 801, 3)
+	if !v.copyFrame() || v.dirty != dirty {
+		t.Fatalf("dirty = %v", v.dirty)
+	}
+	got := v.dirtyPixels(nil)
+	want := append(append([]byte{}, v.pixels[(1*w+799)*4:(2*w)*4]...), v.pixels[(2*w+799)*4:(3*w)*4]...)
+	if !bytes.Equal(got, want) || got[4] != 99 {
+		t.Errorf("dirtyPixels = %v, want %v", got, want)
+	}
+}
+
+func TestViewCopyFrame_DirtyRect(t *testing.T) {
+	origFull, origDirty, origLast := ulViewCopyPixelsRGBA, ulViewCopyPixelsDirty, ulViewGetLastDirty
+	defer func() { ulViewCopyPixelsRGBA, ulViewCopyPixelsDirty, ulViewGetLastDirty = origFull, origDirty, origLast }()
+	var calls []string
+	ulViewCopyPixelsRGBA = func(viewID int32, dest uintptr, destSize int32) int32 {
+		calls = append(calls, "full")
+		return 1
+	}
+	ulViewCopyPixelsDirty = func(viewID int32, dest uintptr, destSize int32) int32 {
+		calls = append(calls, "dirty")
+		return 1
+	}
+	ulViewGetLastDirty = func(viewID int32, out *int32) {
+		copy(unsafe.Slice(out, 4), []int32{1, 2, 3, 9}) // clipped to the 4x4 view
+	}
+	v := newView(0, 4, 4)
+	for i := range v.pixels {
+		v.pixels[i] = byte(i)
+	}
+	if !v.copyFrame() || v.dirty != image.Rect(0, 0, 4, 4) {
+		t.Fatalf("first copy should be full: dirty = %v", v.dirty)
+	}
+	if !v.copyFrame() || v.dirty != image.Rect(1, 2, 3, 4) {
+		t.Fatalf("dirty = %v, want (1,2)-(3,4)", v.dirty)
+	}
+	want := append(append([]byte{}, v.pixels[36:44]...), v.pixels[52:60]...)
+	if got := v.dirtyPixels(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("dirtyPixels = %v, want %v", got, want)
+	}
+	v.setDoubleBuffer(true)
+	v.copyFrame()
+	if want := []string{"full", "dirty", "full"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestLockPixels(t *testing.T) {
+	origGet, origUnlock, origRow, origDestroy := ulViewGetPixels, ulViewUnlockPixels, ulViewGetRowBytes, ulDestroyView
+	defer func() {
+		ulViewGetPixels, ulViewUnlockPixels, ulViewGetRowBytes, ulDestroyView = origGet, origUnlock, origRow, origDestroy
+	}()
+	fakeMessageQueue(t)
+	surface := make([]byte, 4*64)
+	ulViewGetPixels = func(viewID int32) unsafe.Pointer { return unsafe.Pointer(&surface[0]) }
+	ulViewGetRowBytes = func(viewID int32) uint32 { return 64 }
+	unlocks := 0
+	ulViewUnlockPixels = func(viewID int32) { unlocks++ }
+	ulDestroyView = func(viewID int32) {}
+
+	ui := &UltralightUI{view: view{viewID: 3, width: 15, height: 4}}
+	ptr, stride, unlock := ui.LockPixels()
+	if ptr != unsafe.Pointer(&surface[0]) || stride != 64 {
+		t.Fatalf("LockPixels = %p, %d", ptr, stride)
+	}
+	if p, _, _ := ui.LockPixels(); p != nil {
+		t.Error("a locked surface should not be locked again")
+	}
+	unlock()
+	unlock()
+	if unlocks != 1 || !ui.forceCopy {
+		t.Errorf("unlocks = %d, forceCopy = %v", unlocks, ui.forceCopy)
+	}
+
+	ui.LockPixels()
+	unlock() // stale: belongs to the first lock
+	if unlocks != 1 || ui.unlockPixels == nil {
+		t.Fatal("a stale unlock released a newer lock")
+	}
+	ui.Close()
+	if unlocks != 2 {
+		t.Error("Close should unlock a locked surface")
+	}
+	if p, _, unlock := ui.LockPixels(); p != nil || unlock == nil {
+		t.Error("a closed view should return a nil pointer and a no-op unlock")
+	}
+}
+
+func TestSetSizeAndFitToScreen(t *testing.T) {
+	origResize, origSW, origSH := ulViewResize, ulViewGetSurfaceWidth, ulViewGetSurfaceHeight
+	defer func() { ulViewResize, ulViewGetSurfaceWidth, ulViewGetSurfaceHeight = origResize, origSW, origSH }()
+	var resizes [][2]int32
+	ulViewResize = func(viewID int32, width, height int32) int32 {
+		resizes = append(resizes, [2]int32{width, height})
+		return 0
+	}
+	ulViewGetSurfaceWidth = func(viewID int32) int32 { return resizes[len(resizes)-1][0] * 2 }
+	ulViewGetSurfaceHeight = func(viewID int32) int32 { return resizes[len(resizes)-1][1] * 2 }
+
+	ui := &UltralightUI{view: newView(1, 800, 600)}
+	ui.hasFrame = true
+	if err := ui.SetSize(0, 600); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("SetSize(0, 600) = %v, want ErrInvalidSize", err)
+	}
+	if err := ui.SetSize(800, 600); err != nil || len(resizes) != 0 {
+		t.Errorf("unchanged size: err %v, resizes %v", err, resizes)
+	}
+	if err := ui.SetSize(1280, 720); err != nil {
+		t.Fatal(err)
+	}
+	if ui.width != 1280 || len(ui.pixels) != 1280*720*4 || ui.hasFrame || !ui.forceCopy || ui.mouseScale != 2 {
+		t.Errorf("after resize: %dx%d, %d bytes, hasFrame %v, forceCopy %v, mouseScale %v",
+			ui.width, ui.height, len(ui.pixels), ui.hasFrame, ui.forceCopy, ui.mouseScale)
+	}
+
+	resizes = nil
+	for _, size := range [][2]int{{1024, 768}, {1024, 768}, {1024, 768}, {640, 480}} {
+		if err := ui.FitToScreen(size[0], size[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := [][2]int32{{1024, 768}, {640, 480}}; !reflect.DeepEqual(resizes, want) {
+		t.Errorf("resizes = %v, want %v", resizes, want)
+	}
+
+	ulViewResize = nil
+	if err := ui.SetSize(320, 240); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("without ul_view_resize: %v", err)
+	}
+}
+
+func TestViewClipTo(t *testing.T) {
+	v := &view{width: 100, height: 50}
+	atlas := image.Rect(0, 0, 1024, 1024)
+	tests := []struct {
+		db       image.Rectangle
+		x, y     int
+		to, from image.Rectangle
+	}{
+		{atlas, 10, 20, image.Rect(10, 20, 110, 70), image.Rect(0, 0, 100, 50)},
+		{atlas, 1000, 1000, image.Rect(1000, 1000, 1024, 1024), image.Rect(0, 0, 24, 24)},
+		{atlas, -30, 0, image.Rect(0, 0, 70, 50), image.Rect(30, 0, 100, 50)},
+		// Sub-image of an atlas: (x, y) is relative to its bounds.
+		{image.Rect(200, 200, 260, 300), 0, 0, image.Rect(200, 200, 260, 250), image.Rect(0, 0, 60, 50)},
+		{atlas, 2000, 0, image.Rectangle{}, image.Rectangle{}},
+	}
+	for _, tt := range tests {
+		to, from := v.clipTo(tt.db, tt.x, tt.y)
+		if to.Empty() && tt.to.Empty() {
+			continue
+		}
+		if to != tt.to || from != tt.from {
+			t.Errorf("clipTo(%v, %d, %d) = %v, %v; want %v, %v", tt.db, tt.x, tt.y, to, from, tt.to, tt.from)
+		}
+	}
+}
+
+// BenchmarkViewCopyFrame compares full and dirty-rect copies of a 4K view
+// where only a text cursor changes. The fakes convert pixels in Go, standing
+// in for the bridge's BGRA->RGBA loop.
+func BenchmarkViewCopyFrame(b *testing.B) {
+	origFull, origDirty, origLast := ulViewCopyPixelsRGBA, ulViewCopyPixelsDirty, ulViewGetLastDirty
+	defer func() { ulViewCopyPixelsRGBA, ulViewCopyPixelsDirty, ulViewGetLastDirty = origFull, origDirty, origLast }()
+	const w, h = 3840, 2160
+	cursor := image.Rect(600, 400, 602, 420)
+	src := make([]byte, w*h*4)
+	var dst []byte // the view's frame, which the bridge writes through dest
+	convert := func(r image.Rectangle) int32 {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for i := (y*w + r.Min.X) * 4; i < (y*w+r.Max.X)*4; i += 4 {
+				dst[i], dst[i+1], dst[i+2], dst[i+3] = src[i+2], src[i+1], src[i], src[i+3]
+			}
+		}
+		return 1
+	}
+	ulViewCopyPixelsRGBA = func(viewID int32, dest uintptr, destSize int32) int32 {
+		return convert(image.Rect(0, 0, w, h))
+	}
+	ulViewCopyPixelsDirty = func(viewID int32, dest uintptr, destSize int32) int32 {
+		return convert(cursor)
+	}
+	ulViewGetLastDirty = func(viewID int32, out *int32) {
+		copy(unsafe.Slice(out, 4), []int32{600, 400, 602, 420})
+	}
+	for _, partial := range []bool{false, true} {
+		name := "full"
+		if partial {
+			name = "dirty"
+		}
+		b.Run(name, func(b *testing.B) {
+			v := newView(0, w, h)
+			dst = v.pixels
+			v.copyFrame()
+			var buf []byte
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if partial {
+					v.copyFrame()
+					buf = v.dirtyPixels(buf)
+				} else {
+					v.copyFrameWith(ulViewCopyPixelsRGBA)
+				}
+			}
+		})
+	}
+}
+
+func TestFireReady_WarmupBeforeOnReady(t *testing.T) {
+	orig := ulViewEvalJS
+	defer func() { ulViewEvalJS = orig }()
+	var order []string
+	ulViewEvalJS = func(viewID int32, js string) { order = append(order, js) }
+
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+	ui.applyOpts(&Options{WarmupScript: "warm()"})
+	ui.OnReady = func() { ui.Eval("ready()") }
+	ui.fireReady()
+	if len(order) != 2 || order[0] != "warm()" || order[1] != "ready()" {
+		t.Errorf("eval order = %v", order)
+	}
+	if !ui.readyFired {
+		t.Error("readyFired not set")
+	}
+}
+
+func TestHasInputFocus_PerView(t *testing.T) {
+	defer inputFocusViewID.Store(-1)
+	a := &UltralightUI{view: view{viewID: 1}}
+	b := &UltralightUI{view: view{viewID: 2}}
+	a.handleInputFocusMsg(`{"action":"__inputFocus","focused":true}`)
+	b.handleInputFocusMsg(`{"action":"__inputFocus","focused":true}`)
+	if !a.HasInputFocus() || !b.HasInputFocus() {
+		t.Fatal("both views should report input focus")
+	}
+	if inputFocusViewID.Load() != 2 {
+		t.Errorf("global input focus should follow the last view, got %d", inputFocusViewID.Load())
+	}
+	b.handleInputFocusMsg(`{"action":"__inputFocus","focused":false}`)
+	if !a.HasInputFocus() || b.HasInputFocus() {
+		t.Error("blur in b must not affect a")
+	}
+	a.closed.Store(true)
+	if a.HasInputFocus() {
+		t.Error("closed view should not report input focus")
+	}
+}
+
+func TestOnInputFocusChange(t *testing.T) {
+	orig := ulDestroyView
+	defer func() { ulDestroyView = orig; inputFocusViewID.Store(-1) }()
+	ulDestroyView = func(viewID int32) {}
+	fakeMessageQueue(t)
+
+	var got []bool
+	ui := &UltralightUI{view: view{viewID: 4}}
+	ui.OnInputFocusChange = func(focused bool) { got = append(got, focused) }
+	ui.handleInputFocusMsg(`{"action":"__inputFocus","focused":true}`)
+	ui.handleInputFocusMsg(`{"action":"__inputFocus","focused":true}`) // unchanged
+	ui.handleInputFocusMsg(`{"action":"__inputFocus","focused":false}`)
+	ui.handleInputFocusMsg(`{"action":"__inputFocus","focused":true}`)
+	ui.Close()
+	want := []bool{true, false, true, false}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBlurInput(t *testing.T) {
+	defer func() { inputFocusViewID.Store(-1); setFocusedViewID(-1) }()
+	var evals []string
+	captureEvals(t, &evals)
+
+	var got []bool
+	ui := &UltralightUI{view: view{viewID: 4}, domReady: true, goHelperInjected: true}
+	ui.OnInputFocusChange = func(focused bool) { got = append(got, focused) }
+	ui.SetFocus()
+	ui.handleInputFocusMsg(`{"action":"__inputFocus","focused":true}`)
+	ui.BlurInput()
+	if ui.HasInputFocus() || HasInputFocus() {
+		t.Error("HasInputFocus should be false right after BlurInput")
+	}
+	if len(evals) != 1 || !strings.Contains(evals[0], ".blur()") {
+		t.Errorf("evals = %v", evals)
+	}
+	if getFocusedViewID() != 4 {
+		t.Error("BlurInput should keep keyboard focus")
+	}
+	// The page's own blur report changes nothing.
+	ui.handleInputFocusMsg(`{"action":"__inputFocus","focused":false}`)
+	if len(got) != 2 || got[0] != true || got[1] != false {
+		t.Errorf("OnInputFocusChange calls = %v", got)
+	}
+}
+
+func TestBlurOnFocusLoss(t *testing.T) {
+	orig := ulViewEvalJS
+	defer func() { ulViewEvalJS = orig; inputFocusViewID.Store(-1); setFocusedViewID(-1) }()
+	var blurred []int32
+	ulViewEvalJS = func(viewID int32, js string) { blurred = append(blurred, viewID) }
+
+	chat := &UltralightUI{view: view{viewID: 6}, domReady: true, goHelperInjected: true}
+	chat.applyOpts(&Options{BlurOnFocusLoss: true})
+	other := &UltralightUI{view: view{viewID: 7}, domReady: true, goHelperInjected: true}
+	for _, ui := range []*UltralightUI{chat, other} {
+		ui.registerLive()
+		t.Cleanup(ui.unregisterLive)
+	}
+	chat.SetFocus()
+	chat.handleInputFocusMsg(`{"action":"__inputFocus","focused":true}`)
+	other.handleInputFocusMsg(`{"action":"__inputFocus","focused":true}`)
+	chat.SetFocus() // already focused: nothing to blur
+	other.SetFocus()
+	if chat.HasInputFocus() || len(blurred) != 1 || blurred[0] != 6 {
+		t.Errorf("chat should be blurred when losing focus (blurred %v)", blurred)
+	}
+	ClearFocus() // other has no BlurOnFocusLoss
+	if !other.HasInputFocus() || len(blurred) != 1 {
+		t.Errorf("other should keep its field focused (blurred %v)", blurred)
+	}
+}
+
+func TestMarshalRestoreState(t *testing.T) {
+	href := "file:///ui/menu.html#options"
+	fakeEvalSync(t, func(js string) (string, int32) {
+		if js == "location.href" {
+			return href, 0
+		}
+		return `{"url":"` + href + `","scrollX":0,"scrollY":120,"fields":[{"k":"#name","v":"Ana"},{"k":"@sound:1","c":true}]}`, 0
+	})
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{zoom: 1.25}
+	data, err := ui.MarshalState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st viewState
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Version != stateVersion || st.Zoom != 1.25 || st.ScrollY != 120 || len(st.Fields) != 2 || st.Fields[0].Value != "Ana" {
+		t.Fatalf("unexpected state %+v", st)
+	}
+
+	href = "file:///ui/menu.html" // fragment may differ
+	other := &UltralightUI{domReady: true, goHelperInjected: true}
+	if err := other.RestoreState(data); err != nil {
+		t.Fatal(err)
+	}
+	if other.GetZoom() != 1.25 {
+		t.Errorf("zoom = %v, want 1.25", other.GetZoom())
+	}
+	// The ready view applies the zoom right away, then the restore script.
+	if len(evals) != 2 || !strings.Contains(evals[1], `"#options"`) || !strings.Contains(evals[1], `"Ana"`) {
+		t.Errorf("restore script missing state: %v", evals)
+	}
+
+	href = "file:///ui/hud.html"
+	if err := other.RestoreState(data); err == nil {
+		t.Error("expected error for a different document")
+	}
+	if err := other.RestoreState([]byte(`{"version":99}`)); err == nil {
+		t.Error("expected error for an unknown version")
+	}
+}
+
+func TestInjectCSS(t *testing.T) {
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{}
+	a, _ := ui.InjectCSS(`body{color:"red"}`)
+	b, _ := ui.InjectCSS("p{}")
+	c, _ := ui.InjectCSS("a{}")
+	ui.RemoveCSS(b)
+	if len(evals) != 0 || a == b || b == c {
+		t.Fatalf("nothing should be evaluated before DOM ready (handles %d %d %d)", a, b, c)
+	}
+	ui.domReady, ui.goHelperInjected = true, true
+	ui.applyPendingCSS()
+	if len(evals) != 2 || !strings.Contains(evals[0], `body{color:\"red\"}`) || !strings.Contains(evals[1], "a{}") {
+		t.Fatalf("pending CSS not applied in order: %v", evals)
+	}
+	ui.RemoveCSS(a)
+	if len(evals) != 3 || !strings.Contains(evals[2], `data-ul-id="`+strconv.Itoa(a)+`"`) {
+		t.Errorf("remove script = %v", evals[len(evals)-1])
+	}
+	ui.closed.Store(true)
+	if _, err := ui.InjectCSS("x{}"); err != ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestWithBaseHref(t *testing.T) {
+	base := `<base href="file:///ui/">`
+	cases := []struct{ in, want string }{
+		{`<html><HEAD lang="x"><title>t</title></head></html>`, `<html><HEAD lang="x">` + base + `<title>t</title></head></html>`},
+		{`<html><header></header></html>`, `<html>` + base + `<header></header></html>`},
+		{`<img src="logo.png">`, base + `<img src="logo.png">`},
+		{"<!DOCTYPE html>\n<p>hi</p>", "<!DOCTYPE html>" + base + "\n<p>hi</p>"},
+		{`<!doctype html><html><body></body></html>`, `<!doctype html><html>` + base + `<body></body></html>`},
+	}
+	for _, c := range cases {
+		if got := string(withBaseHref([]byte(c.in), "file:///ui/")); got != c.want {
+			t.Errorf("withBaseHref(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+	if got := string(withBaseHref(nil, `a"b`)); got != `<base href="a&#34;b">` {
+		t.Errorf("href not escaped: %q", got)
+	}
+}
+
+func TestActiveViewCount(t *testing.T) {
+	orig := ulDestroyView
+	defer func() { ulDestroyView = orig }()
+	ulDestroyView = func(viewID int32) {}
+	fakeMessageQueue(t)
+
+	before := ActiveViewCount()
+	registerView()
+	ui := &UltralightUI{view: view{viewID: 9}}
+	if ActiveViewCount() != before+1 {
+		t.Fatalf("count = %d, want %d", ActiveViewCount(), before+1)
+	}
+	ui.Close()
+	ui.Close()
+	if ActiveViewCount() != before {
+		t.Errorf("count after Close = %d, want %d", ActiveViewCount(), before)
+	}
+}
+
+func TestClickCounter(t *testing.T) {
+	var c clickCounter
+	t0 := time.Unix(0, 0)
+	if n := c.press(t0, 10, 10); n != 1 {
+		t.Fatalf("first press = %d", n)
+	}
+	if n := c.press(t0.Add(multiClickWindow), 10+multiClickSlop, 10); n != 2 {
+		t.Fatalf("press at the edge of the window/slop = %d, want 2", n)
+	}
+	if n := c.press(t0.Add(multiClickWindow+100*time.Millisecond), 12, 11); n != 3 {
+		t.Fatalf("third press = %d, want 3", n)
+	}
+	if n := c.press(t0.Add(multiClickWindow+200*time.Millisecond), 12, 11); n != 1 {
+		t.Errorf("fourth press should start over, got %d", n)
+	}
+	if n := c.press(t0.Add(multiClickWindow+300*time.Millisecond), 12+multiClickSlop+1, 11); n != 1 {
+		t.Errorf("press beyond slop = %d, want 1", n)
+	}
+	c.press(t0, 0, 0)
+	if n := c.press(t0.Add(multiClickWindow+time.Millisecond), 0, 0); n != 1 {
+		t.Errorf("press after the window = %d, want 1", n)
+	}
+}
+
+func TestForwardExtraButtons_Release(t *testing.T) {
+	type ev struct {
+		button  int
+		pressed bool
+		x, y    int
+	}
+	var got []ev
+	ui := &UltralightUI{}
+	ui.OnMouseButton = func(button int, pressed bool, x, y int) { got = append(got, ev{button, pressed, x, y}) }
+	ui.extraDown[1] = true // forward button pressed inside on an earlier frame
+	if !ui.anyButtonDown() {
+		t.Fatal("a held extra button should keep input captured")
+	}
+	ui.forwardExtraButtons(false, 30, 40) // released, even outside the bounds
+	if len(got) != 1 || got[0] != (ev{5, false, 30, 40}) || ui.anyButtonDown() {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestKeyRepeats_ConsistentAcrossTPS(t *testing.T) {
+	delay, interval := 500*time.Millisecond, 50*time.Millisecond
+	for _, tps := range []float64{30, 60, 120, 240} {
+		total, first := 0, 0
+		for dur := 1; dur <= int(tps); dur++ { // hold for one second
+			n := keyRepeats(dur, tps, delay, interval)
+			if n > 0 && first == 0 {
+				first = dur
+			}
+			total += n
+		}
+		// Repeats at 500, 550, ..., 1000 ms.
+		if total != 11 {
+			t.Errorf("tps %v: %d repeats in 1s, want 11", tps, total)
+		}
+		if got := time.Duration(float64(first) / tps * float64(time.Second)); got != delay {
+			t.Errorf("tps %v: first repeat after %v, want %v", tps, got, delay)
+		}
+	}
+	if keyRepeats(1, 60, 0, interval) != 0 {
+		t.Error("the press tick must not repeat")
+	}
+}
+
+func TestSetFocusable(t *testing.T) {
+	defer setFocusedViewID(-1)
+	ui := &UltralightUI{view: view{viewID: 5}}
+	ui.SetFocus()
+	ui.SetFocusable(false)
+	if getFocusedViewID() == 5 {
+		t.Fatal("making the view non-focusable should clear its focus")
+	}
+	ui.SetFocus()
+	if getFocusedViewID() == 5 {
+		t.Fatal("SetFocus on a non-focusable view must be a no-op")
+	}
+	ui.SetFocusable(true)
+	ui.SetFocus()
+	if getFocusedViewID() != 5 {
+		t.Error("focusable again: SetFocus should work")
+	}
+}
+
+func TestPinchTracker(t *testing.T) {
+	var p pinchTracker
+	ids := [2]ebiten.TouchID{1, 2}
+	if _, ok := p.update(ids, image.Pt(0, 0), image.Pt(100, 0)); ok {
+		t.Fatal("the first frame only starts the gesture")
+	}
+	if _, ok := p.update(ids, image.Pt(0, 0), image.Pt(100, 0)); ok {
+		t.Fatal("unchanged distance should not report")
+	}
+	if s, ok := p.update(ids, image.Pt(0, 0), image.Pt(150, 0)); !ok || s != 1.5 {
+		t.Fatalf("spread = %v, %v; want 1.5", s, ok)
+	}
+	if s, ok := p.update(ids, image.Pt(0, 0), image.Pt(0, 75)); !ok || s != 0.5 {
+		t.Fatalf("pinch = %v, %v; want 0.5", s, ok)
+	}
+	if _, ok := p.update([2]ebiten.TouchID{1, 3}, image.Pt(0, 0), image.Pt(10, 0)); ok {
+		t.Error("a different finger pair restarts the gesture")
+	}
+}
+
+func TestClose_DrainsMessages(t *testing.T) {
+	orig := ulDestroyView
+	defer func() { ulDestroyView = orig }()
+	var got []string
+	ulDestroyView = func(viewID int32) { got = append(got, "destroy") }
+	fakeMessageQueue(t, `{"state":1}`, "quit")
+
+	ui := &UltralightUI{}
+	ui.OnMessage = func(msg string) {
+		got = append(got, msg)
+		if msg == "quit" {
+			ui.Close() // re-entrant Close from a handler returns at once
+		}
+	}
+	ui.Close()
+	want := []string{`{"state":1}`, "quit", "destroy"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFireKey(t *testing.T) {
+	orig := ulViewFireKey
+	defer func() { ulViewFireKey = orig }()
+	var got []string
+	ulViewFireKey = func(viewID int32, keyType int32, vk int32, mods uint32, text string) {
+		got = append(got, fmt.Sprintf("%d:%d:%#x:%d:%q", viewID, keyType, vk, mods, text))
+	}
+	ui := &UltralightUI{view: view{viewID: 3}}
+	ui.FireKey(KeyEventRawKeyDown, 0x41, KeyModCtrl|KeyModShift, "a")
+	ui.closed.Store(true)
+	ui.FireKey(KeyEventKeyUp, 0x41, 0, "a")
+	if len(got) != 1 || got[0] != `3:0:0x41:10:"a"` {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestFireMouse(t *testing.T) {
+	orig := ulViewFireMouse
+	defer func() { ulViewFireMouse = orig }()
+	var got [][5]int32
+	ulViewFireMouse = func(viewID int32, eventType, x, y, button int32) {
+		got = append(got, [5]int32{viewID, eventType, x, y, button})
+	}
+	ui := &UltralightUI{view: view{viewID: 2}}
+	ui.FireMouse(MouseEventDown, 10, 20, MouseButtonLeft)
+	ui.FireMouse(MouseEventUp, 10, 20, MouseButtonLeft)
+	if len(got) != 2 || got[0] != [5]int32{2, 1, 10, 20, 1} || got[1][1] != 2 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestHitTest(t *testing.T) {
+	ui := &UltralightUI{BoundsX: 0, BoundsY: 0, BoundsW: 4, BoundsH: 2}
+	ui.view = newView(0, 4, 2)
+	if !ui.hitTest(3, 1) {
+		t.Fatal("no hit rect: whole bounds should hit")
+	}
+	ui.SetHitRect(2, 0, 2, 2)
+	if ui.hitTest(1, 1) || !ui.hitTest(2, 1) {
+		t.Fatal("hit rect not applied")
+	}
+	if ui.hitTest(4, 1) {
+		t.Fatal("outside the bounds must not hit")
+	}
+
+	ui.SetAlphaHitTest(true)
+	if ui.hitTest(2, 1) {
+		t.Fatal("no frame yet: nothing is opaque")
+	}
+	ui.hasFrame = true
+	ui.pixels[(1*4+3)*4+3] = 255 // (3,1) opaque
+	if ui.hitTest(2, 1) || !ui.hitTest(3, 1) {
+		t.Error("alpha hit test should only accept opaque pixels")
+	}
+	ui.SetHitRect(0, 0, 0, 0)
+	ui.SetAlphaHitTest(false)
+	if !ui.hitTest(0, 0) {
+		t.Error("restrictions should be removable")
+	}
+}
+
+func TestClickThroughAlpha(t *testing.T) {
+	ui := &UltralightUI{view: newView(0, 2, 1)}
+	ui.hasFrame = true
+	ui.pixels[3] = 100 // (0,0) semi-transparent
+	ui.pixels[7] = 200 // (1,0)
+	ui.SetClickThroughAlpha(128)
+	if ui.hitTest(0, 0) || !ui.hitTest(1, 0) {
+		t.Error("pixels below the threshold should pass input through")
+	}
+	ui.SetClickThroughAlpha(0)
+	if !ui.hitTest(0, 0) {
+		t.Error("threshold 0 disables click-through")
+	}
+}
+
+func TestLoadEvents(t *testing.T) {
+	cases := []struct {
+		prev, cur loadCounters
+		want      []loadEvent
+	}{
+		{loadCounters{}, loadCounters{}, nil},
+		{loadCounters{}, loadCounters{starts: 1}, []loadEvent{loadStarted}},
+		{loadCounters{starts: 1}, loadCounters{1, 1, 0}, []loadEvent{loadFinished}},
+		{loadCounters{}, loadCounters{1, 1, 0}, []loadEvent{loadStarted, loadFinished}},
+		// The open load ends before the next one starts.
+		{loadCounters{starts: 1}, loadCounters{2, 0, 1}, []loadEvent{loadFailed, loadStarted}},
+		{loadCounters{1, 1, 0}, loadCounters{3, 2, 1}, []loadEvent{loadStarted, loadFinished, loadStarted, loadFailed}},
+	}
+	for i, c := range cases {
+		if got := loadEvents(c.prev, c.cur); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("case %d: got %v, want %v", i, got, c.want)
+		}
+	}
+}
+
+func TestLoadProgress(t *testing.T) {
+	orig := ulViewGetLoadState
+	t.Cleanup(func() { ulViewGetLoadState = orig })
+	state := [4]int32{100, 1, 0, 0}
+	ulViewGetLoadState = func(viewID int32, out *int32) {
+		copy(unsafe.Slice(out, 4), state[:])
+	}
+	ui := &UltralightUI{view: view{viewID: 1}}
+	var events []string
+	ui.OnLoadStart = func() { events = append(events, "start") }
+	ui.OnLoadFinish = func() { events = append(events, "finish") }
+
+	ui.pollLoadEvents()
+	if p := ui.LoadProgress(); p != 0.1 {
+		t.Errorf("LoadProgress = %v, want 0.1", p)
+	}
+	state = [4]int32{1000, 1, 1, 0}
+	ui.pollLoadEvents()
+	ui.pollLoadEvents()
+	if p := ui.LoadProgress(); p != 1 {
+		t.Errorf("LoadProgress = %v, want 1", p)
+	}
+	if want := []string{"start", "finish"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestHistoryNavigation(t *testing.T) {
+	orig := ulViewNavigate
+	t.Cleanup(func() { ulViewNavigate = orig })
+	var ops []int32
+	ulViewNavigate = func(viewID int32, op int32) int32 {
+		ops = append(ops, op)
+		return map[int32]int32{navCanBack: 1, navCanForward: 0}[op]
+	}
+	ui := &UltralightUI{view: view{viewID: 2}}
+	if !ui.CanGoBack() || ui.CanGoForward() {
+		t.Error("CanGoBack/CanGoForward should report the bridge answer")
+	}
+	ui.GoBack()
+	ui.GoForward()
+	if want := []int32{navCanBack, navCanForward, navBack, navForward}; !reflect.DeepEqual(ops, want) {
+		t.Errorf("ops = %v, want %v", ops, want)
+	}
+	ui.closed.Store(true)
+	ui.GoBack()
+	if ui.CanGoBack() || len(ops) != 4 {
+		t.Error("closed view should not reach the bridge")
+	}
+}
+
+func TestStop_FiresLoadErrorThenFinish(t *testing.T) {
+	origNav, origState, origErr := ulViewNavigate, ulViewGetLoadState, ulViewGetLoadError
+	t.Cleanup(func() { ulViewNavigate, ulViewGetLoadState, ulViewGetLoadError = origNav, origState, origErr })
+	state := [4]int32{100, 1, 0, 0}
+	ulViewGetLoadState = func(viewID int32, out *int32) { copy(unsafe.Slice(out, 4), state[:]) }
+	ulViewNavigate = func(viewID int32, op int32) int32 {
+		if op == navStop {
+			state = [4]int32{1000, 1, 0, 1}
+		}
+		return 0
+	}
+	ulViewGetLoadError = func(viewID int32, urlBuf *byte, urlSize int32, descBuf *byte, descSize int32) int32 {
+		unsafe.Slice(urlBuf, urlSize)[0] = 0
+		copy(unsafe.Slice(descBuf, descSize), "Load stopped\x00")
+		return LoadErrorStopped
+	}
+	ui := &UltralightUI{view: view{viewID: 1}}
+	var events []string
+	ui.OnLoadStart = func() { events = append(events, "start") }
+	ui.OnLoadError = func(url, desc string, code int) { events = append(events, fmt.Sprintf("error %q %q %d", url, desc, code)) }
+	ui.OnLoadFinish = func() { events = append(events, "finish") }
+
+	ui.pollLoadEvents()
+	ui.Stop()
+	ui.pollLoadEvents()
+	want := []string{"start", `error "" "Load stopped" -999`, "finish"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestEvalSend_QueuedUntilReady(t *testing.T) {
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{}
+	ui.Eval("a()")
+	ui.Send(map[string]int{"hp": 3})
+	ui.Eval("b()")
+	if len(evals) != 0 {
+		t.Fatalf("nothing should run before the page is ready: %v", evals)
+	}
+	ui.domReady, ui.goHelperInjected = true, true
+	ui.flushPreReady()
+	ui.Eval("c()")
+	if len(evals) != 4 || evals[0] != "a()" || !strings.Contains(evals[1], `{"hp":3}`) || evals[2] != "b()" || evals[3] != "c()" {
+		t.Errorf("evals = %q", evals)
+	}
+}
+
+func TestWaitReady(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ui := &UltralightUI{}
+	if err := ui.WaitReady(ctx); !errors.Is(err, ErrNotReady) || !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: got %v", err)
+	}
+	ui.domReady, ui.goHelperInjected = true, true
+	if err := ui.WaitReady(ctx); err != nil {
+		t.Errorf("ready view: got %v", err)
+	}
+	ui.closed.Store(true)
+	if err := ui.WaitReady(context.Background()); err != ErrClosed {
+		t.Errorf("closed view: got %v", err)
+	}
+}
+
+func TestCheckDOMReady(t *testing.T) {
+	origReady, origDOM := ulViewIsReady, ulViewIsDOMReady
+	t.Cleanup(func() { ulViewIsReady, ulViewIsDOMReady = origReady, origDOM })
+	ready, dom := int32(1), int32(0)
+	ulViewIsReady = func(viewID int32) int32 { return ready }
+	ulViewIsDOMReady = func(viewID int32) int32 { return dom }
+
+	ui := &UltralightUI{frameCount: 1}
+	if ui.checkDOMReady() {
+		t.Error("DOM not parsed yet")
+	}
+	dom = 1
+	if !ui.checkDOMReady() {
+		t.Error("DOMReady signal should not wait for the frame fallback")
+	}
+	ready = 0
+	if ui.checkDOMReady() {
+		t.Error("a view still loading is never DOM ready")
+	}
+	ready, dom = 1, -1 // no signal in this SDK
+	ui.frameCount = domReadyFallbackFrames + 1
+	if !ui.checkDOMReady() {
+		t.Error("fallback should flip after domReadyFallbackFrames")
+	}
+}
+
+func TestOptionalSymbols_Missing(t *testing.T) {
+	origInit, origVer, origSync := ulInit, ulBridgeVersion, ulViewEvalSync
+	origTyped, origReg, origNav := ulVfsRegisterTyped, ulVfsRegister, ulViewNavigate
+	t.Cleanup(func() {
+		ulInit, ulBridgeVersion, ulViewEvalSync = origInit, origVer, origSync
+		ulVfsRegisterTyped, ulVfsRegister, ulViewNavigate = origTyped, origReg, origNav
+	})
+	resetVFSRegistry(t)
+	ulInit = func(baseDir string, debug int32) int32 { return 0 }
+	ulBridgeVersion, ulViewEvalSync, ulVfsRegisterTyped, ulViewNavigate = nil, nil, nil, nil
+	var registered []string
+	ulVfsRegister = func(path string, data uintptr, size int64) int32 {
+		registered = append(registered, path)
+		return 0
+	}
+
+	if _, err := BridgeVersion(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("BridgeVersion: got %v", err)
+	}
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+	if _, err := ui.EvalSync("1"); !errors.Is(err, ErrUnsupported) || !strings.Contains(err.Error(), "ul_view_eval_sync") {
+		t.Errorf("EvalSync: got %v", err)
+	}
+	if err := RegisterFile("ui/a.css", []byte("a{}")); err != nil || len(registered) != 1 {
+		t.Errorf("RegisterFile should fall back to ul_vfs_register: %v %v", err, registered)
+	}
+	if err := RegisterFileWithType("ui/m", []byte("x"), "text/javascript"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("RegisterFileWithType: got %v", err)
+	}
+	if ui.CanGoBack() {
+		t.Error("CanGoBack without the bridge symbol should be false")
+	}
+	ui.GoBack() // must not panic
+
+	ulBridgeVersion = func() string { return "1.1.0" }
+	if v, err := BridgeVersion(); err != nil || v != "1.1.0" {
+		t.Errorf("BridgeVersion = %q, %v", v, err)
+	}
+}
+
+func TestHandleNavigateMsg(t *testing.T) {
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+	var asked []string
+	ui.OnNavigationRequest = func(url string) bool {
+		asked = append(asked, url)
+		return !strings.HasPrefix(url, "https://")
+	}
+	if !ui.handleNavigateMsg(`{"action":"__navigate","url":"https://store.example.com/"}`) || len(evals) != 0 {
+		t.Errorf("cancelled navigation should be consumed without loading: %v", evals)
+	}
+	if !ui.handleNavigateMsg(`{"action":"__navigate","url":"file:///ui/shop.html"}`) ||
+		len(evals) != 1 || evals[0] != `location.href="file:///ui/shop.html"` {
+		t.Errorf("allowed navigation: evals = %v", evals)
+	}
+	if len(asked) != 2 {
+		t.Errorf("asked = %v", asked)
+	}
+	if ui.handleNavigateMsg(`{"action":"buy"}`) {
+		t.Error("other messages must reach OnMessage")
+	}
+}
+
+func TestHandleResourceMsg(t *testing.T) {
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+	var asked []ResourceRequest
+	ui.OnResourceRequest = func(req ResourceRequest) (string, bool) {
+		asked = append(asked, req)
+		if strings.Contains(req.URL, "tracker") {
+			return "", true
+		}
+		if rest, ok := strings.CutPrefix(req.URL, "https://cdn.example.com/"); ok {
+			return "file:///cdn/" + rest, false
+		}
+		return "", false
+	}
+	msgs := []string{
+		`{"action":"__resource","id":1,"url":"https://cdn.example.com/lib.js","method":"GET","type":"fetch"}`,
+		`{"action":"__resource","id":2,"url":"https://tracker.example.com/p","method":"POST","type":"xhr"}`,
+		`{"action":"__resource","id":3,"url":"file:///ui/data.json","method":"GET","type":"xhr"}`,
+	}
+	for _, m := range msgs {
+		if !ui.handleResourceMsg(m) {
+			t.Fatalf("%s should be consumed", m)
+		}
+	}
+	want := []string{
+		`window.__ulResReply&&__ulResReply(1,"file:///cdn/lib.js",false)`,
+		`window.__ulResReply&&__ulResReply(2,"https://tracker.example.com/p",true)`,
+		`window.__ulResReply&&__ulResReply(3,"file:///ui/data.json",false)`,
+	}
+	if !reflect.DeepEqual(evals, want) {
+		t.Errorf("evals = %v, want %v", evals, want)
+	}
+	if len(asked) != 3 || asked[1].Method != "POST" || asked[1].Type != "xhr" {
+		t.Errorf("asked = %+v", asked)
+	}
+	if ui.handleResourceMsg(`{"action":"buy"}`) {
+		t.Error("other messages must reach OnMessage")
+	}
+}
+
+func TestPostTo(t *testing.T) {
+	orig := ulViewEvalJS
+	defer func() { ulViewEvalJS = orig }()
+	evals := map[int32][]string{}
+	ulViewEvalJS = func(viewID int32, js string) { evals[viewID] = append(evals[viewID], js) }
+
+	sidebar := &UltralightUI{view: view{viewID: 1}, domReady: true, goHelperInjected: true}
+	main := &UltralightUI{view: view{viewID: 2}, domReady: true, goHelperInjected: true}
+	if err := sidebar.PostTo(main, map[string]string{"selected": "sword"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(evals[1]) != 0 || len(evals[2]) != 1 ||
+		!strings.Contains(evals[2][0], "g.onPeerMessage(d)") || !strings.HasSuffix(evals[2][0], `})({"selected":"sword"});`) {
+		t.Errorf("evals = %v", evals)
+	}
+	if err := sidebar.PostTo(nil, 1); err == nil {
+		t.Error("nil target should fail")
+	}
+	if err := sidebar.PostTo(main, func() {}); err == nil {
+		t.Error("unserializable data should fail")
+	}
+	main.closed.Store(true)
+	if err := sidebar.PostTo(main, 1); !errors.Is(err, ErrClosed) {
+		t.Errorf("closed target: %v", err)
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	orig := ulViewEvalJS
+	defer func() { ulViewEvalJS = orig }()
+	evals := map[int32][]string{}
+	ulViewEvalJS = func(viewID int32, js string) { evals[viewID] = append(evals[viewID], js) }
+
+	var views []*UltralightUI
+	for id := int32(1); id <= 3; id++ {
+		ui := &UltralightUI{view: view{viewID: id}, domReady: true, goHelperInjected: true}
+		ui.registerLive()
+		t.Cleanup(ui.unregisterLive)
+		views = append(views, ui)
+	}
+	views[1].unregisterLive() // closed
+	views[2].closed.Store(true)
+	if got := Views(); len(got) != 1 || got[0] != views[0] {
+		t.Errorf("Views = %v", got)
+	}
+	if ui, ok := ViewByID(1); !ok || ui != views[0] || ui.ID() != 1 {
+		t.Errorf("ViewByID(1) = %v, %v", ui, ok)
+	}
+	for _, id := range []int32{2, 3, 99} {
+		if _, ok := ViewByID(id); ok {
+			t.Errorf("ViewByID(%d) should not find a closed or unknown view", id)
+		}
+	}
+	if err := Broadcast(map[string]string{"locale": "es"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(evals[1]) != 1 || !strings.Contains(evals[1][0], `window.go.receive({"locale":"es"})`) {
+		t.Errorf("open view: %v", evals[1])
+	}
+	if len(evals[2]) != 0 || len(evals[3]) != 0 {
+		t.Errorf("closed views should be skipped: %v", evals)
+	}
+	if err := Broadcast(func() {}); err == nil {
+		t.Error("unserializable data should fail")
+	}
+}
+
+func TestLogger(t *testing.T) {
+	origLevel, origMsg, origState, origErr := ulViewGetConsoleLevel, ulViewGetConsoleMessage, ulViewGetLoadState, ulViewGetLoadError
+	t.Cleanup(func() {
+		ulViewGetConsoleLevel, ulViewGetConsoleMessage, ulViewGetLoadState, ulViewGetLoadError = origLevel, origMsg, origState, origErr
+	})
+	type entry struct {
+		msg   string
+		level int32
+	}
+	queue := []entry{{"ready", consoleLog}, {"low memory", consoleWarning}, {"TypeError: x is undefined", consoleError}}
+	ulViewGetConsoleLevel = func(viewID int32, buf uintptr, bufSize int32, level *int32) int32 {
+		if len(queue) == 0 {
+			return 0
+		}
+		e := queue[0]
+		queue = queue[1:]
+		*level = e.level
+		dst := unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&buf))), bufSize)
+		return int32(copy(dst, e.msg))
+	}
+	ulViewGetLoadState = func(viewID int32, out *int32) { copy(unsafe.Slice(out, 4), []int32{1000, 1, 0, 1}) }
+	ulViewGetLoadError = func(viewID int32, urlBuf *byte, urlSize int32, descBuf *byte, descSize int32) int32 {
+		copy(unsafe.Slice(urlBuf, urlSize), "https://example.com/\x00")
+		copy(unsafe.Slice(descBuf, descSize), "Could not resolve host\x00")
+		return -6
+	}
+
+	var out bytes.Buffer
+	ui := &UltralightUI{view: view{viewID: 4}}
+	ui.applyOpts(&Options{Logger: slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))})
+	ui.pollConsole()
+	ui.pollLoadEvents()
+	logged := out.String()
+	for _, want := range []string{
+		`level=INFO msg=ready view=4 source=console`,
+		`level=WARN msg="low memory" view=4`,
+		`level=ERROR msg="TypeError: x is undefined"`,
+		`level=WARN msg="load failed" view=4 url=https://example.com/ description="Could not resolve host" code=-6`,
+	} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("log missing %q:\n%s", want, logged)
+		}
+	}
+
+	// Without a Logger the queue is still drained.
+	queue = []entry{{"dropped", consoleLog}}
+	(&UltralightUI{view: view{viewID: 5}}).pollConsole()
+	if len(queue) != 0 {
+		t.Error("console messages should be drained without a Logger")
+	}
+	if _, debug := resolveOpts(&Options{Debug: true, Logger: slog.Default()}); debug {
+		t.Error("a Logger should turn off the on-disk debug logs")
+	}
+}
+
+func TestNeedsRedraw(t *testing.T) {
+	orig := ulViewNeedsPaint
+	t.Cleanup(func() { ulViewNeedsPaint = orig })
+	pending := map[int32]bool{}
+	ulViewNeedsPaint = func(viewID int32) int32 {
+		if pending[viewID] {
+			return 1
+		}
+		return 0
+	}
+	a := &UltralightUI{view: view{viewID: 6}, domReady: true}
+	b := &UltralightUI{view: view{viewID: 7}, domReady: true}
+	for _, ui := range []*UltralightUI{a, b} {
+		ui.registerLive()
+		t.Cleanup(ui.unregisterLive)
+	}
+	if a.NeedsRedraw() || AnyNeedsRedraw() {
+		t.Error("idle views should not need a redraw")
+	}
+	pending[7] = true
+	if a.NeedsRedraw() || !b.NeedsRedraw() || !AnyNeedsRedraw() {
+		t.Error("only the view with pending work should need a redraw")
+	}
+	b.closed.Store(true)
+	if b.NeedsRedraw() {
+		t.Error("a closed view should not need a redraw")
+	}
+	if loading := (&UltralightUI{view: view{viewID: 8}}); !loading.NeedsRedraw() {
+		t.Error("a view whose DOM isn't ready should need a redraw")
+	}
+	ulViewNeedsPaint = nil
+	if !a.NeedsRedraw() {
+		t.Error("without ul_view_needs_paint a view should always need a redraw")
+	}
+}
+
+// BenchmarkIdleTick measures a frame of an idle, fully loaded view when the
+// host ticks every frame and when it ticks only while AnyNeedsRedraw reports
+// work, as a free-running (vsync off) game would. Needs the Ultralight SDK.
+func BenchmarkIdleTick(b *testing.B) {
+	requireSDK(b)
+	ui, err := newUI(800, 600, []byte(`<h1 style="color:#fff">Idle</h1>`), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ui.Close()
+	if !ui.waitForPaint(RenderOnceTimeout) {
+		b.Fatal("the page never painted")
+	}
+	ui.domReady = true // waitForPaint saw the view ready
+	for _, mode := range []string{"always", "needsRedraw"} {
+		b.Run(mode, func(b *testing.B) {
+			ticks := 0
+			for i := 0; i < b.N; i++ {
+				if mode == "always" || AnyNeedsRedraw() {
+					ulTick()
+					ticks++
+				}
+				ui.copyFrame()
+			}
+			b.ReportMetric(float64(ticks)/float64(b.N), "ticks/op")
+		})
+	}
+}
+
+func TestSelection(t *testing.T) {
+	const result = "to be or not"
+	fakeEvalSync(t, func(js string) (string, int32) { return result, 0 })
+	var evals []string
+	captureEvals(t, &evals)
+
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+	if got, err := ui.GetSelectionText(); err != nil || got != result {
+		t.Errorf("GetSelectionText = %q, %v", got, err)
+	}
+	ui.SelectRange(`#quote p[data-x="1"]`, 3, -2)
+	if len(evals) != 1 || !strings.HasSuffix(evals[0], `})("#quote p[data-x=\"1\"]",3,0)`) {
+		t.Errorf("SelectRange script = %v", evals)
+	}
+}
+
+func TestSmoothScroller(t *testing.T) {
+	var s smoothScroller
+	s.add(-100)
+	keep := scrollKeep(0, 60)
+	total, steps := int32(0), 0
+	for ; steps < 100; steps++ {
+		dy := s.step(keep)
+		if dy == 0 {
+			break
+		}
+		if dy > 0 {
+			t.Fatalf("step %d scrolled the wrong way: %d", steps, dy)
+		}
+		total += dy
+	}
+	if total != -100 || steps < 5 {
+		t.Errorf("scrolled %d px in %d steps, want -100 over several steps", total, steps)
+	}
+
+	// Reversing direction drops the rest of the previous scroll.
+	s.add(-100)
+	s.step(keep)
+	s.add(50)
+	if s.pending != 50 {
+		t.Errorf("pending after reverse = %v, want 50", s.pending)
+	}
+
+	// At 120 TPS two ticks keep as much as one 60 Hz frame.
+	if k := scrollKeep(0.8, 120); math.Abs(k*k-0.8) > 1e-9 {
+		t.Errorf("scrollKeep(0.8, 120) = %v", k)
+	}
+}
+
+func TestCreateError(t *testing.T) {
+	cases := []struct {
+		code int32
+		want error
+	}{
+		{-1, ErrRendererNotInitialized},
+		{-2, ErrViewLimitReached},
+		{-3, ErrInvalidSize},
+		{-11, ErrViewCreateFailed},
+		{-12, ErrOutOfMemory},
+	}
+	for _, c := range cases {
+		if err := createError("ul_create_view_with_html", c.code); !errors.Is(err, c.want) {
+			t.Errorf("createError(%d) = %v, want %v", c.code, err, c.want)
+		}
+	}
+	err := createError("ul_create_view_with_url", -42)
+	for _, c := range cases {
+		if errors.Is(err, c.want) {
+			t.Errorf("unknown code matched %v", c.want)
+		}
+	}
+	if !strings.Contains(err.Error(), "-42") {
+		t.Errorf("unknown code error = %q, want the raw code", err)
+	}
+
+	if _, err := newUI(0, 600, []byte("<p>x</p>"), nil); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("newUI(0, 600) error = %v, want ErrInvalidSize", err)
+	}
+}
+
+func TestRendererInfo(t *testing.T) {
+	origInit, origInfo := ulInit, ulRendererInfo
+	t.Cleanup(func() { ulInit, ulRendererInfo = origInit, origInfo })
+	ulInit, ulRendererInfo = nil, nil
+	if _, _, err := RendererInfo(); err == nil {
+		t.Error("RendererInfo should fail before the bridge is loaded")
+	}
+	ulInit = func(baseDir string, debug int32) int32 { return 0 }
+	if _, _, err := RendererInfo(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("RendererInfo without symbol: %v", err)
+	}
+	ulRendererInfo = func(buf *byte, bufSize int32) int32 { return -1 }
+	if _, _, err := RendererInfo(); !errors.Is(err, ErrRendererNotInitialized) {
+		t.Errorf("RendererInfo before init: %v", err)
+	}
+	ulRendererInfo = func(buf *byte, bufSize int32) int32 {
+		copy(unsafe.Slice(buf, bufSize), "cpu\x00")
+		return 0
+	}
+	if backend, gpu, err := RendererInfo(); err != nil || backend != "cpu" || gpu {
+		t.Errorf("RendererInfo = %q, %v, %v", backend, gpu, err)
+	}
+}
+
+func TestMemoryUsage(t *testing.T) {
+	origInit, origView, origTotal := ulInit, ulViewMemoryUsage, ulMemoryUsageTotal
+	t.Cleanup(func() { ulInit, ulViewMemoryUsage, ulMemoryUsageTotal = origInit, origView, origTotal })
+	ulInit = func(baseDir string, debug int32) int32 { return 0 }
+
+	ulViewMemoryUsage, ulMemoryUsageTotal = nil, nil
+	ui := &UltralightUI{view: view{viewID: 3}}
+	if _, err := ui.MemoryUsage(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("MemoryUsage without symbol: %v", err)
+	}
+	if _, err := TotalMemoryUsage(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("TotalMemoryUsage without symbol: %v", err)
+	}
+
+	ulViewMemoryUsage = func(viewID int32) int64 {
+		if viewID == 3 {
+			return 1920000
+		}
+		return -1
+	}
+	ulMemoryUsageTotal = func() int64 { return 2500000 }
+	if n, err := ui.MemoryUsage(); err != nil || n != 1920000 {
+		t.Errorf("MemoryUsage = %d, %v", n, err)
+	}
+	if n, err := TotalMemoryUsage(); err != nil || n != 2500000 {
+		t.Errorf("TotalMemoryUsage = %d, %v", n, err)
+	}
+	if _, err := (&UltralightUI{view: view{viewID: 9}}).MemoryUsage(); err == nil {
+		t.Error("MemoryUsage of an unknown view should fail")
+	}
+	ui.closed.Store(true)
+	if _, err := ui.MemoryUsage(); !errors.Is(err, ErrClosed) {
+		t.Errorf("MemoryUsage after Close: %v", err)
+	}
+}
+
+func TestUpdate_ForcedCopyAfterHidden(t *testing.T) {
+	origMsg, origReady, origState := ulViewGetMessage, ulViewIsReady, ulViewGetLoadState
+	origCopy, origForce := ulViewCopyPixelsRGBA, ulViewCopyPixelsForce
+	t.Cleanup(func() {
+		ulViewGetMessage, ulViewIsReady, ulViewGetLoadState = origMsg, origReady, origState
+		ulViewCopyPixelsRGBA, ulViewCopyPixelsForce = origCopy, origForce
+	})
+	ulViewGetMessage = func(viewID int32, buf uintptr, bufSize int32) int32 { return 0 }
+	ulViewIsReady = func(viewID int32) int32 { return 0 }
+	ulViewGetLoadState = nil
+	var copies, forced int
+	ulViewCopyPixelsRGBA = func(viewID int32, dest uintptr, destSize int32) int32 { copies++; return 0 }
+	ulViewCopyPixelsForce = func(viewID int32, dest uintptr, destSize int32) int32 { forced++; return 1 }
+
+	ui := &UltralightUI{view: newView(0, 1, 1)}
+	ui.updateInternal() // hidden: zero bounds
+	if copies != 0 || forced != 0 {
+		t.Fatalf("hidden view copied pixels (%d, %d)", copies, forced)
+	}
+	ui.SetBounds(0, 0, 1, 1)
+	ui.updateInternal()
+	if forced != 1 || copies != 0 || !ui.hasFrame {
+		t.Errorf("un-hide: forced = %d, copies = %d; want one forced copy", forced, copies)
+	}
+	ui.updateInternal()
+	if forced != 1 || copies != 1 {
+		t.Errorf("next frame: forced = %d, copies = %d; want a normal copy", forced, copies)
+	}
+}
+
+func TestDisableEditHelpers(t *testing.T) {
+	origMsg, origReady, origDOM, origState := ulViewGetMessage, ulViewIsReady, ulViewIsDOMReady, ulViewGetLoadState
+	origCopy := ulViewCopyPixelsRGBA
+	t.Cleanup(func() {
+		ulViewGetMessage, ulViewIsReady, ulViewIsDOMReady, ulViewGetLoadState = origMsg, origReady, origDOM, origState
+		ulViewCopyPixelsRGBA = origCopy
+	})
+	ulViewGetMessage = func(viewID int32, buf uintptr, bufSize int32) int32 { return 0 }
+	ulViewIsReady = func(viewID int32) int32 { return 1 }
+	ulViewIsDOMReady = func(viewID int32) int32 { return 1 }
+	ulViewGetLoadState = nil
+	ulViewCopyPixelsRGBA = func(viewID int32, dest uintptr, destSize int32) int32 { return 0 }
+	var evals []string
+	captureEvals(t, &evals)
+	undoHelper := func() bool {
+		for _, js := range evals {
+			if strings.Contains(js, "__ulUndoInit") {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, disabled := range []bool{false, true} {
+		evals = nil
+		ui := &UltralightUI{view: newView(0, 1, 1)}
+		ui.applyOpts(&Options{DisableEditHelpers: disabled})
+		ui.updateInternal() // DOM ready
+		if !ui.goHelperInjected || undoHelper() == disabled {
+			t.Errorf("disabled=%v: DOM ready installed the edit helpers = %v", disabled, undoHelper())
+		}
+		evals = nil
+		ui.resetPageHelpers() // navigation
+		if undoHelper() == disabled {
+			t.Errorf("disabled=%v: navigation installed the edit helpers = %v", disabled, undoHelper())
+		}
+	}
+}
+
+func TestMouseEnterLeave_Edges(t *testing.T) {
+	orig := ulViewFireMouse
+	defer func() { ulViewFireMouse = orig }()
+	var moves []image.Point
+	ulViewFireMouse = func(viewID int32, eventType, x, y, button int32) {
+		if eventType == MouseEventMoved {
+			moves = append(moves, image.Pt(int(x), int(y)))
+		}
+	}
+	ui := &UltralightUI{view: view{width: 100, height: 50}, mouseScale: 1}
+	ui.SetBounds(200, 100, 100, 50)
+	ui.mouseX, ui.mouseY = -1, -1
+
+	// Enter from the left edge: a move at the entry point, then normal moves.
+	ui.mouseInside = true
+	ui.fireMouseMove(0, 20, true)
+	ui.fireMouseMove(0, 20, false) // no movement, no event
+	ui.fireMouseMove(5, 20, false)
+
+	// Leave through the right edge: the cursor is now at screen x=320.
+	ui.fireMouseLeave(320, 130)
+	want := []image.Point{{0, 20}, {5, 20}, {99, 30}, {-1, -1}}
+	if !reflect.DeepEqual(moves, want) {
+		t.Fatalf("moves = %v, want %v", moves, want)
+	}
+	if ui.mouseInside || ui.mouseX != -1 {
+		t.Error("leave should reset the hover state")
+	}
+
+	// Re-enter from the bottom at the same point it left: the move still fires.
+	moves = nil
+	ui.mouseInside = true
+	ui.fireMouseMove(99, 49, true)
+	if len(moves) != 1 || moves[0] != image.Pt(99, 49) {
+		t.Errorf("re-enter moves = %v", moves)
+	}
+
+	// Leaving from the last in-bounds point skips the duplicate edge move.
+	moves = nil
+	ui.fireMouseLeave(299, 160)
+	if want := []image.Point{{-1, -1}}; !reflect.DeepEqual(moves, want) {
+		t.Errorf("leave at last point moves = %v, want %v", moves, want)
+	}
+}
+
+func TestCharFilter(t *testing.T) {
+	orig := ulViewFireKey
+	defer func() { ulViewFireKey = orig }()
+	var chars []string
+	ulViewFireKey = func(viewID int32, keyType int32, vk int32, mods uint32, text string) {
+		if keyType == KeyEventChar {
+			chars = append(chars, text)
+		}
+	}
+
+	ui := &UltralightUI{}
+	for _, r := range []rune{'a', '\t', 0x01, 0x7F, 'é'} {
+		if got, want := ui.acceptsChar(r), r == 'a' || r == 'é'; got != want {
+			t.Errorf("default acceptsChar(%q) = %v", r, got)
+		}
+	}
+	ui.fireTabChar(ebiten.KeyTab)
+	if len(chars) != 0 {
+		t.Errorf("default filter typed a tab: %q", chars)
+	}
+
+	ui.applyOpts(&Options{CharFilter: func(r rune) bool { return r == '\t' || r >= 0x20 }})
+	ui.fireTabChar(ebiten.KeyTab)
+	ui.fireTabChar(ebiten.KeyEnter)
+	if !reflect.DeepEqual(chars, []string{"\t"}) {
+		t.Errorf("chars = %q, want one tab", chars)
+	}
+}
+
+func TestQuerySelectorAndGetAttribute(t *testing.T) {
+	var script, result string
+	fakeEvalSync(t, func(js string) (string, int32) { script = js; return result, 0 })
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+
+	result = "1"
+	if ok, err := ui.QuerySelector(`button[data-id="buy"]`); err != nil || !ok {
+		t.Errorf("QuerySelector = %v, %v", ok, err)
+	}
+	if !strings.Contains(script, `document.querySelector("button[data-id=\"buy\"]")`) {
+		t.Errorf("selector not escaped: %s", script)
+	}
+	result = "0"
+	if ok, _ := ui.QuerySelector("#missing"); ok {
+		t.Error("QuerySelector should report a missing element")
+	}
+
+	result = `["gold"]`
+	if v, err := ui.GetAttribute("#coin", "data-kind"); err != nil || v != "gold" {
+		t.Errorf("GetAttribute = %q, %v", v, err)
+	}
+	result = `[""]`
+	if v, err := ui.GetAttribute("#opt", "checked"); err != nil || v != "" {
+		t.Errorf("empty attribute = %q, %v", v, err)
+	}
+	result = `[null]`
+	if _, err := ui.GetAttribute("#opt", "checked"); !errors.Is(err, ErrAttributeNotFound) {
+		t.Errorf("missing attribute error = %v", err)
+	}
+	result = `null`
+	if _, err := ui.GetAttribute("#nope", "id"); !errors.Is(err, ErrElementNotFound) {
+		t.Errorf("missing element error = %v", err)
+	}
+}
+
+func TestDeviceScale(t *testing.T) {
+	origCreate, origSet := ulSetViewDeviceScale, ulViewSetDeviceScale
+	defer func() { ulSetViewDeviceScale, ulViewSetDeviceScale = origCreate, origSet }()
+	var created, set []int32
+	ulSetViewDeviceScale = func(permille int32) { created = append(created, permille) }
+	ulViewSetDeviceScale = func(viewID int32, permille int32) int32 {
+		set = append(set, permille)
+		return 0
+	}
+
+	createView(&Options{DeviceScale: 2}, func() int32 { return 0 })
+	createView(nil, func() int32 { return 1 })
+	if !reflect.DeepEqual(created, []int32{2000, 1000}) {
+		t.Errorf("create scales = %v, want [2000 1000]", created)
+	}
+
+	ui := &UltralightUI{}
+	ui.applyOpts(&Options{DeviceScale: 1.5})
+	if ui.DeviceScale() != 1.5 {
+		t.Errorf("DeviceScale = %v, want 1.5", ui.DeviceScale())
+	}
+	if err := ui.SetDeviceScale(100); err != nil || ui.DeviceScale() != maxDeviceScale || set[0] != 8000 {
+		t.Errorf("SetDeviceScale(100): %v, scale %v, sent %v", err, ui.DeviceScale(), set)
+	}
+
+	ulViewSetDeviceScale = func(viewID int32, permille int32) int32 { return -3 }
+	if err := ui.SetDeviceScale(2); !errors.Is(err, ErrUnsupported) || ui.DeviceScale() != maxDeviceScale {
+		t.Errorf("SDK without ulViewSetDeviceScale: %v, scale %v", err, ui.DeviceScale())
+	}
+	ulSetViewDeviceScale, ulViewSetDeviceScale = nil, nil
+	ui = &UltralightUI{}
+	ui.applyOpts(&Options{DeviceScale: 2})
+	if ui.DeviceScale() != 1 {
+		t.Errorf("old bridge: DeviceScale = %v, want 1", ui.DeviceScale())
+	}
+	if err := ui.SetDeviceScale(2); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("old bridge: SetDeviceScale error = %v", err)
+	}
+}
+
+func TestSession(t *testing.T) {
+	origSet, origDestroy, origView, origMsg := ulSetViewSession, ulDestroySession, ulDestroyView, ulViewGetMessage
+	defer func() {
+		ulSetViewSession, ulDestroySession, ulDestroyView, ulViewGetMessage = origSet, origDestroy, origView, origMsg
+	}()
+	ulViewGetMessage = func(viewID int32, buf uintptr, bufSize int32) int32 { return 0 }
+	var sessionOfCreate []int32
+	ulSetViewSession = func(id int32) { sessionOfCreate = append(sessionOfCreate, id) }
+	var destroyedSessions []int32
+	ulDestroySession = func(id int32) int32 { destroyedSessions = append(destroyedSessions, id); return 0 }
+	var destroyedViews []int32
+	ulDestroyView = func(viewID int32) { destroyedViews = append(destroyedViews, viewID) }
+
+	mods := &Session{id: 2, name: "mods", views: map[*UltralightUI]struct{}{}}
+	opts := &Options{Session: mods}
+	newView := func(id int32, opts *Options) *UltralightUI {
+		if got := createView(opts, func() int32 { return id }); got != id {
+			t.Fatalf("createView = %d", got)
+		}
+		ui := &UltralightUI{view: view{viewID: id}}
+		ui.applyOpts(opts)
+		return ui
+	}
+	a, b := newView(3, opts), newView(4, opts)
+	game := newView(5, nil)
+	if !reflect.DeepEqual(sessionOfCreate, []int32{2, 2, 0}) || mods.ViewCount() != 2 {
+		t.Fatalf("sessions = %v, count = %d", sessionOfCreate, mods.ViewCount())
+	}
+
+	a.Close()
+	if mods.ViewCount() != 1 || len(destroyedSessions) != 0 {
+		t.Fatalf("closing one view: count = %d, destroyed sessions = %v", mods.ViewCount(), destroyedSessions)
+	}
+	b.updating.Store(true) // b is in an Update: destroyed when it returns
+	mods.Close()
+	if len(destroyedSessions) != 0 {
+		t.Fatal("session destroyed before its last view")
+	}
+	b.endUpdate()
+	if !reflect.DeepEqual(destroyedSessions, []int32{2}) || !reflect.DeepEqual(destroyedViews, []int32{3, 4}) {
+		t.Errorf("destroyed sessions = %v, views = %v", destroyedSessions, destroyedViews)
+	}
+	if game.closed.Load() {
+		t.Error("closing a session must not close views of other sessions")
+	}
+	mods.Close() // idempotent
+	if len(destroyedSessions) != 1 {
+		t.Error("session destroyed twice")
+	}
+
+	code := createView(opts, func() int32 { t.Fatal("created a view in a closed session"); return 0 })
+	if err := createError("ul_create_view_with_html", code); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("create in closed session: %v", err)
+	}
+
+	// The session closes while the view is being created.
+	racy := &Session{id: 6, views: map[*UltralightUI]struct{}{}}
+	ropts := &Options{Session: racy}
+	createView(ropts, func() int32 { racy.Close(); return 7 })
+	late := &UltralightUI{view: view{viewID: 7}}
+	if err := late.applyOpts(ropts); !errors.Is(err, ErrSessionClosed) || !late.closed.Load() {
+		t.Errorf("session closed during create: err = %v, closed = %v", err, late.closed.Load())
+	}
+}
+
+func TestEnsureULInit_RetriesUntilSuccess(t *testing.T) {
+	origInit, origDone := ulInit, ulInitDone
+	defer func() { ulInit, ulInitDone = origInit, origDone }()
+	ulInitDone = false
+	var dirs []string
+	ulInit = func(baseDir string, debug int32) int32 {
+		dirs = append(dirs, baseDir)
+		if baseDir != "/opt/game/libs" {
+			return -3
+		}
+		return 0
+	}
+
+	if err := ensureULInit("/wrong", false); err == nil || !strings.Contains(err.Error(), "/wrong") {
+		t.Fatalf("bad base dir: %v", err)
+	}
+	if err := ensureULInit("/opt/game/libs", false); err != nil {
+		t.Fatalf("retry with the right dir: %v", err)
+	}
+	if err := ensureULInit("/elsewhere", false); err != nil {
+		t.Fatalf("after success: %v", err)
+	}
+	if want := []string{"/wrong", "/opt/game/libs"}; !reflect.DeepEqual(dirs, want) {
+		t.Errorf("ul_init calls = %v, want %v", dirs, want)
+	}
+}
+
+func TestCreateView_UserAgent(t *testing.T) {
+	orig := ulSetViewUserAgent
+	defer func() { ulSetViewUserAgent = orig }()
+	var agents []string
+	ulSetViewUserAgent = func(ua string) { agents = append(agents, ua) }
+
+	createView(&Options{UserAgent: "MyGame/1.2 (Ultralight)"}, func() int32 { return 0 })
+	createView(nil, func() int32 { return 1 })
+	if want := []string{"MyGame/1.2 (Ultralight)", ""}; !reflect.DeepEqual(agents, want) {
+		t.Errorf("user agents = %q, want %q", agents, want)
+	}
+}
+
+func TestCreateView_DefaultFontFamily(t *testing.T) {
+	orig := ulSetViewFontFamily
+	defer func() { ulSetViewFontFamily = orig }()
+	var families []string
+	ulSetViewFontFamily = func(family string) { families = append(families, family) }
+
+	createView(&Options{DefaultFontFamily: "Inter"}, func() int32 { return 0 })
+	createView(&Options{UserAgent: "MyGame/1.2"}, func() int32 { return 1 })
+	if want := []string{"Inter", ""}; !reflect.DeepEqual(families, want) {
+		t.Errorf("font families = %q, want %q", families, want)
+	}
+}
+
+func TestInputMode(t *testing.T) {
+	defer setFocusedViewID(-1)
+	ui := &UltralightUI{view: view{viewID: 9}}
+	tests := []struct {
+		mode                           InputMode
+		focus, scroll, hoverKeys, keys bool // keys: with keyboard focus
+	}{
+		{InputDefault, true, true, false, true},
+		{InputMouseOnly, false, false, false, false},
+		{InputMouseAndScroll, false, true, false, false},
+		{InputFull, false, true, true, true},
+	}
+	for _, tt := range tests {
+		ui.SetInputMode(tt.mode)
+		setFocusedViewID(-1)
+		if ui.takesFocusOnClick() != tt.focus || ui.takesScroll() != tt.scroll ||
+			ui.takesKeyboard(true) != tt.hoverKeys || ui.takesKeyboard(false) {
+			t.Errorf("mode %d without focus: focus %v, scroll %v, hover keys %v, keys %v", tt.mode,
+				ui.takesFocusOnClick(), ui.takesScroll(), ui.takesKeyboard(true), ui.takesKeyboard(false))
+		}
+		setFocusedViewID(ui.viewID)
+		if ui.takesKeyboard(false) != tt.keys {
+			t.Errorf("mode %d with focus: keys %v, want %v", tt.mode, ui.takesKeyboard(false), tt.keys)
+		}
+	}
+}
+
+func TestClickThroughEmpty(t *testing.T) {
+	var result, script string
+	fakeEvalSync(t, func(js string) (string, int32) { script = js; return result, 0 })
+
+	ui := &UltralightUI{}
+	result = "1"
+	if ui.emptyAt(10, 20) || script != "" {
+		t.Fatal("emptyAt must not hit-test before the DOM is ready")
+	}
+	ui.domReady = true
+	if !ui.emptyAt(10, 20) {
+		t.Fatal("expected a click on <body> to be empty")
+	}
+	if !strings.Contains(script, "elementFromPoint(10/r,20/r)") {
+		t.Fatalf("unexpected hit-test script %q", script)
+	}
+	result = "0"
+	if ui.emptyAt(10, 20) {
+		t.Fatal("expected a click on an element to be handled by the view")
+	}
+
+	// A passed-through press keeps the cursor inside: no mouseleave, no re-enter.
+	ui.mouseInside = true
+	if ui.passEmptyPress(10, 20, true, false, false) {
+		t.Fatal("press passed through with the option off")
+	}
+	ui.SetClickThroughEmpty(true)
+	if ui.passEmptyPress(10, 20, true, false, false) || ui.leftOutside {
+		t.Fatal("press on an element passed through")
+	}
+	result = "1"
+	if !ui.passEmptyPress(10, 20, true, false, false) {
+		t.Fatal("press on <body> not passed through")
+	}
+	if !ui.leftOutside || ui.rightOutside || !ui.mouseInside {
+		t.Errorf("leftOutside=%v rightOutside=%v mouseInside=%v", ui.leftOutside, ui.rightOutside, ui.mouseInside)
+	}
+	ui.leftOutside, ui.leftDown = false, true
+	if ui.passEmptyPress(10, 20, false, true, false) {
+		t.Error("press during a captured drag passed through")
+	}
+}
+
+func TestCheckRenderer(t *testing.T) {
+	for _, r := range []Renderer{RendererAuto, RendererCPU} {
+		if err := checkRenderer(&Options{Renderer: r}); err != nil {
+			t.Fatalf("%v: %v", r, err)
+		}
+	}
+	if err := checkRenderer(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkRenderer(&Options{Renderer: RendererGPU}); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("GPU renderer: got %v, want ErrUnsupported", err)
+	}
+	if _, err := newUI(100, 100, nil, &Options{Renderer: RendererGPU}); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("newUI with GPU renderer: got %v", err)
+	}
+	if err := checkRenderer(&Options{Renderer: 7}); err == nil || !strings.Contains(err.Error(), "Renderer(7)") {
+		t.Fatalf("unknown renderer: got %v", err)
+	}
+}
+
+func TestAnimationPending(t *testing.T) {
+	ui := &UltralightUI{}
+	if !ui.AnimationPending() || !ui.animTracking {
+		t.Fatal("AnimationPending must start tracking and report true until the page reports")
+	}
+	if !ui.handleAnimationMsg(`{"action":"__animation","pending":false}`) {
+		t.Fatal("animation message not consumed")
+	}
+	if ui.AnimationPending() {
+		t.Error("idle page reported an animation")
+	}
+	ui.handleAnimationMsg(`{"action":"__animation","pending":true}`)
+	if !ui.AnimationPending() {
+		t.Error("running animation not reported")
+	}
+	if ui.handleAnimationMsg(`{"action":"tick"}`) {
+		t.Error("regular messages must not be consumed")
+	}
+
+	ui.handleAnimationMsg(`{"action":"__animation","pending":false}`)
+	ui.animHelperInjected = true
+	ui.resetPageHelpers()
+	if ui.animHelperInjected || !ui.AnimationPending() {
+		t.Error("navigation must reinject the helper and forget the old page's state")
+	}
+	ui.closed.Store(true)
+	if ui.AnimationPending() {
+		t.Error("closed views never have an animation pending")
+	}
+}
+
+func TestDisableJavaScript(t *testing.T) {
+	origVer, origJS, origSync := ulBridgeVersion, ulViewEvalJS, ulViewEvalSync
+	defer func() { ulBridgeVersion, ulViewEvalJS, ulViewEvalSync = origVer, origJS, origSync }()
+
+	opts := &Options{DisableJavaScript: true}
+	for _, tc := range []struct {
+		version string
+		ok      bool
+	}{{"1.15.0", false}, {"1.16.0", true}, {"1.16.2", true}, {"2.0", true}, {"1.9.9", false}} {
+		ulBridgeVersion = func() string { return tc.version }
+		if err := checkJavaScript(opts); (err == nil) != tc.ok || (err != nil && !errors.Is(err, ErrUnsupported)) {
+			t.Errorf("bridge %s: got %v", tc.version, err)
+		}
+	}
+	ulBridgeVersion = nil
+	if err := checkJavaScript(opts); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("bridge without ul_bridge_version: got %v", err)
+	}
+	if err := checkJavaScript(&Options{}); err != nil {
+		t.Errorf("JavaScript enabled: got %v", err)
+	}
+	if err := createError("ul_create_view_with_html", -13); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("code -13: got %v", err)
+	}
+
+	evals := 0
+	ulViewEvalJS = func(viewID int32, js string) { evals++ }
+	ulViewEvalSync = func(viewID int32, js string) int32 {
+		t.Fatal("EvalSync reached the bridge")
+		return 0
+	}
+	ui := &UltralightUI{domReady: true, goHelperInjected: true}
+	ui.applyOpts(opts)
+	ui.Eval("document.title='x'")
+	if err := ui.Send(map[string]int{"hp": 1}); err != nil {
+		t.Fatal(err)
+	}
+	ui.injectGoHelper()
+	if evals != 0 || len(ui.preReady) != 0 {
+		t.Errorf("scripts ran or were queued: evals=%d queued=%d", evals, len(ui.preReady))
+	}
+	if _, err := ui.EvalSync("1+1"); !errors.Is(err, ErrJavaScriptDisabled) {
+		t.Errorf("EvalSync: got %v", err)
+	}
+	if ui.AnimationPending() {
+		t.Error("AnimationPending must be false without JavaScript: the page can't report")
+	}
+}
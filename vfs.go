@@ -4,35 +4,177 @@
 package ultralightui
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"hash/maphash"
+	"io"
 	"io/fs"
+	"log"
+	"mime"
+	"net/http"
 	"path"
+	"sort"
 	"strings"
+	"sync"
 	"unsafe"
+)
+
+// fallbackMimeTypes covers extensions that mime.TypeByExtension may not know
+// on every platform (the system MIME tables differ between OSes).
+var fallbackMimeTypes = map[string]string{
+	".mjs":   "text/javascript",
+	".wasm":  "application/wasm",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+}
 
-	"github.com/hajimehoshi/ebiten/v2"
+// detectMimeType returns the content type for filePath based on its extension,
+// without parameters (charset is reported separately to Ultralight).
+// Returns "" when unknown, letting the bridge apply its own extension table.
+func detectMimeType(filePath string) string {
+	ext := strings.ToLower(path.Ext(filePath))
+	if ext == "" {
+		return ""
+	}
+	t := mime.TypeByExtension(ext)
+	if t == "" {
+		t = fallbackMimeTypes[ext]
+	}
+	if i := strings.IndexByte(t, ';'); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+	return t
+}
+
+// vfsEntry is what the Go-side registry knows about a registered file.
+type vfsEntry struct {
+	size int
+	sum  uint64 // maphash of the content
+	mime string
+}
+
+// The VFS content lives in the bridge; vfsFiles mirrors it so Go can list it
+// and skip re-registering identical files (e.g. NewFromFS for every view of
+// the same embed). vfsMu also serializes calls into the bridge's VFS table.
+var (
+	vfsMu    sync.Mutex
+	vfsFiles = map[string]vfsEntry{}
+	vfsSeed  = maphash.MakeSeed()
 )
 
 // RegisterFile registers a file in Ultralight's VFS.
 // filePath is the virtual path (e.g., "ui/style.css"). data is the content.
 // Registered files take priority over disk files.
 // Must be called BEFORE creating views that reference them.
+// The content type is inferred by the bridge from the extension; use
+// RegisterFileWithType for files without an extension or with unusual ones.
 func RegisterFile(filePath string, data []byte) error {
+	return RegisterFileWithType(filePath, data, "")
+}
+
+// RegisterFileWithType is like RegisterFile but serves the file with an
+// explicit content type (e.g., "text/javascript" for an extensionless ES module).
+// An empty mimeType falls back to extension-based detection in the bridge.
+// Registering identical content and type again for the same path is a no-op.
+func RegisterFileWithType(filePath string, data []byte, mimeType string) error {
+	_, err := registerVFS(filePath, data, mimeType)
+	return err
+}
+
+// registerVFS is RegisterFileWithType, also reporting whether the path held
+// different content that was replaced.
+func registerVFS(filePath string, data []byte, mimeType string) (replaced bool, err error) {
 	if len(data) == 0 {
-		return nil
+		return false, nil
 	}
-	norm := strings.ReplaceAll(filePath, "\\", "/")
-	norm = strings.TrimLeft(norm, "/")
-	rc := ulVfsRegister(norm, uintptr(unsafe.Pointer(&data[0])), int64(len(data)))
-	if rc != 0 {
-		return fmt.Errorf("ul_vfs_register failed for %q: code %d", norm, rc)
+	norm := vfsPath(filePath)
+	entry := vfsEntry{size: len(data), sum: maphash.Bytes(vfsSeed, data), mime: mimeType}
+	vfsMu.Lock()
+	defer vfsMu.Unlock()
+	old, exists := vfsFiles[norm]
+	if exists && old == entry {
+		if keepVFSContent && vfsContent[norm] == nil {
+			vfsContent[norm] = bytes.Clone(data)
+		}
+		return false, nil
+	}
+	if ulVfsRegisterTyped == nil {
+		if mimeType != "" {
+			return false, errUnsupported("ul_vfs_register_typed")
+		}
+		if rc := ulVfsRegister(norm, uintptr(unsafe.Pointer(&data[0])), int64(len(data))); rc != 0 {
+			return false, fmt.Errorf("ul_vfs_register failed for %q: code %d", norm, rc)
+		}
+	} else {
+		rc := ulVfsRegisterTyped(norm, uintptr(unsafe.Pointer(&data[0])), int64(len(data)), mimeType)
+		if rc != 0 {
+			return false, fmt.Errorf("ul_vfs_register_typed failed for %q: code %d", norm, rc)
+		}
+	}
+	vfsFiles[norm] = entry
+	if keepVFSContent {
+		vfsContent[norm] = bytes.Clone(data)
+	}
+	return exists && (old.size != entry.size || old.sum != entry.sum), nil
+}
+
+// registerFSFile registers a file found while walking an FS, warning when it
+// replaces different content: usually two sources sharing a path. The warning
+// goes to opts.Logger, or to the standard logger if only opts.Debug is set.
+func registerFSFile(p string, data []byte, opts *Options) error {
+	replaced, err := registerVFS(p, data, detectMimeType(p))
+	if replaced && opts != nil {
+		if opts.Logger != nil {
+			opts.Logger.Warn("VFS file replaced by different content", "path", vfsPath(p))
+		} else if opts.Debug {
+			log.Printf("ultralightui: VFS file %q replaced by different content", vfsPath(p))
+		}
+	}
+	return err
+}
+
+// ErrFileNotRegistered is returned by UnregisterFile for a path that is not
+// in the VFS.
+var ErrFileNotRegistered = errors.New("ultralightui: file not registered in the VFS")
+
+// vfsNotRegistered is ul_vfs_unregister's code for an unknown path.
+const vfsNotRegistered = -4
+
+// UnregisterFile removes one file from the VFS and frees its data, e.g. to
+// replace a stylesheet at runtime or drop assets no longer needed. filePath
+// is normalized like in RegisterFile. Pages already loaded keep what they
+// read; later requests for the path fall back to disk.
+func UnregisterFile(filePath string) error {
+	if ulVfsUnregister == nil {
+		return errUnsupported("ul_vfs_unregister")
+	}
+	norm := vfsPath(filePath)
+	vfsMu.Lock()
+	defer vfsMu.Unlock()
+	switch rc := ulVfsUnregister(norm); rc {
+	case 0:
+		delete(vfsFiles, norm)
+		delete(vfsContent, norm)
+		return nil
+	case vfsNotRegistered:
+		delete(vfsFiles, norm)
+		delete(vfsContent, norm)
+		return fmt.Errorf("%w: %q", ErrFileNotRegistered, norm)
+	default:
+		return fmt.Errorf("ul_vfs_unregister failed for %q: code %d", norm, rc)
 	}
-	return nil
 }
 
 // ClearFiles frees all files registered in the VFS.
 func ClearFiles() {
+	vfsMu.Lock()
+	defer vfsMu.Unlock()
 	ulVfsClear()
+	clear(vfsFiles)
+	clear(vfsContent)
 }
 
 // VFSFileCount returns the number of files registered in the VFS.
@@ -40,12 +182,27 @@ func VFSFileCount() int {
 	return int(ulVfsCount())
 }
 
+// VFSFiles returns the normalized paths of the files registered in the VFS,
+// sorted, e.g. to check that a //go:embed pattern picked up what the page
+// references. Pages request them as file:///<path>.
+func VFSFiles() []string {
+	vfsMu.Lock()
+	defer vfsMu.Unlock()
+	files := make([]string, 0, len(vfsFiles))
+	for p := range vfsFiles {
+		files = append(files, p)
+	}
+	sort.Strings(files)
+	return files
+}
+
 // NewFromFS creates a new UI loading all files from the given fs.FS
 // into Ultralight's VFS, then loads mainFile as the main page.
 //
 // mainFile is relative to the FS root (e.g., "ui/index.html").
 // All files in the FS are registered so that <link>, <script>, <img>
-// can reference them with relative paths.
+// can reference them with relative paths. Each file is served with a content
+// type detected from its extension (including .mjs, .wasm and .woff2).
 //
 // Example with embed.FS:
 //
@@ -54,55 +211,121 @@ func VFSFileCount() int {
 //	ui, err := ultralightui.NewFromFS(800, 600, "ui/index.html", uiFiles, nil)
 func NewFromFS(width, height int, mainFile string, fsys fs.FS, opts *Options) (*UltralightUI, error) {
 	if width <= 0 || height <= 0 {
-		return nil, fmt.Errorf("invalid dimensions: %dx%d", width, height)
+		return nil, sizeError(width, height)
 	}
 	baseDir, debug := resolveOpts(opts)
-	if err := initBridge(baseDir); err != nil {
+	if err := initBridge(bridgePath(opts, baseDir)); err != nil {
 		return nil, fmt.Errorf("bridge: %w", err)
 	}
 	if err := ensureULInit(baseDir, debug); err != nil {
 		return nil, err
 	}
 
-	// Walk the FS and register each file
+	if err := registerFS(fsys, opts); err != nil {
+		return nil, err
+	}
+	return newUIWithURL(width, height, vfsURL(mainFile), opts)
+}
+
+// NewFromHTTPFS is like NewFromFS for an http.FileSystem (e.g. http.Dir or
+// a legacy asset package): every file reachable from its root is registered,
+// then mainFile is loaded. http.FileSystem names are rooted ("/ui/app.js");
+// they are registered without the leading slash, so "ui/index.html" and
+// "/ui/index.html" name the same mainFile.
+func NewFromHTTPFS(width, height int, mainFile string, hfs http.FileSystem, opts *Options) (*UltralightUI, error) {
+	if width <= 0 || height <= 0 {
+		return nil, sizeError(width, height)
+	}
+	baseDir, debug := resolveOpts(opts)
+	if err := initBridge(bridgePath(opts, baseDir)); err != nil {
+		return nil, fmt.Errorf("bridge: %w", err)
+	}
+	if err := ensureULInit(baseDir, debug); err != nil {
+		return nil, err
+	}
+	if err := registerHTTPFS(hfs, "/", opts); err != nil {
+		return nil, fmt.Errorf("walking HTTP FS: %w", err)
+	}
+	return newUIWithURL(width, height, vfsURL(mainFile), opts)
+}
+
+// vfsFilter returns Options.VFSFilter, or nil to register every file.
+func vfsFilter(opts *Options) func(string) bool {
+	if opts == nil {
+		return nil
+	}
+	return opts.VFSFilter
+}
+
+// vfsPath normalizes a file name to its VFS path, as RegisterFile does.
+func vfsPath(name string) string {
+	return strings.TrimLeft(strings.ReplaceAll(name, "\\", "/"), "/")
+}
+
+// registerFS registers every file of fsys accepted by opts.VFSFilter (nil
+// accepts all) in the VFS under its fs.FS path.
+func registerFS(fsys fs.FS, opts *Options) error {
+	filter := vfsFilter(opts)
 	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if d.IsDir() {
+		if d.IsDir() || (filter != nil && !filter(vfsPath(p))) {
 			return nil
 		}
 		data, readErr := fs.ReadFile(fsys, p)
 		if readErr != nil {
 			return fmt.Errorf("reading %s: %w", p, readErr)
 		}
-		return RegisterFile(p, data)
+		return registerFSFile(p, data, opts)
 	})
 	if err != nil {
-		return nil, fmt.Errorf("walking FS: %w", err)
+		return fmt.Errorf("walking FS: %w", err)
 	}
+	return nil
+}
 
-	norm := path.Clean(strings.ReplaceAll(mainFile, "\\", "/"))
-	norm = strings.TrimLeft(norm, "/")
-	url := "file:///" + norm
-
-	// Combined create+load in ONE worker roundtrip, no sleeping
-	viewID := ulCreateViewWithURL(int32(width), int32(height), url)
-	if viewID < 0 {
-		return nil, fmt.Errorf("ul_create_view_with_url failed with code %d", viewID)
+// registerHTTPFS registers the files accepted by opts.VFSFilter in the file or
+// directory tree at name (rooted, as http.FileSystem expects) in the VFS.
+// http.FileSystem has no WalkDir, so directories are listed with Readdir.
+func registerHTTPFS(hfs http.FileSystem, name string, opts *Options) error {
+	f, err := hfs.Open(name)
+	if err != nil {
+		return err
 	}
-	registerView()
-
-	ui := &UltralightUI{
-		viewID:  viewID,
-		texture: ebiten.NewImage(width, height),
-		pixels:  make([]byte, width*height*4),
-		width:   width,
-		height:  height,
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
 	}
-	ui.detectMouseScale()
+	if !info.IsDir() {
+		if filter := vfsFilter(opts); filter != nil && !filter(vfsPath(name)) {
+			return nil
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		return registerFSFile(name, data, opts)
+	}
+	entries, err := f.Readdir(-1)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := registerHTTPFS(hfs, path.Join(name, e.Name()), opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	return ui, nil
+// vfsURL returns the file:/// URL of mainFile in the VFS. Backslashes become
+// slashes, the path is cleaned and leading slashes are dropped, matching the
+// names RegisterFile uses.
+func vfsURL(mainFile string) string {
+	norm := path.Clean(strings.ReplaceAll(mainFile, "\\", "/"))
+	return "file:///" + strings.TrimLeft(norm, "/")
 }
 
 // NewFromFSAsync is like NewFromFS but creates the view asynchronously.
@@ -112,53 +335,35 @@ func NewFromFS(width, height int, mainFile string, fsys fs.FS, opts *Options) (*
 // Pixel output will be empty/transparent until the view is ready.
 func NewFromFSAsync(width, height int, mainFile string, fsys fs.FS, opts *Options) (*UltralightUI, error) {
 	if width <= 0 || height <= 0 {
-		return nil, fmt.Errorf("invalid dimensions: %dx%d", width, height)
+		return nil, sizeError(width, height)
 	}
 	baseDir, debug := resolveOpts(opts)
-	if err := initBridge(baseDir); err != nil {
+	if err := initBridge(bridgePath(opts, baseDir)); err != nil {
 		return nil, fmt.Errorf("bridge: %w", err)
 	}
 	if err := ensureULInit(baseDir, debug); err != nil {
 		return nil, err
 	}
 
-	// Walk the FS and register each file
-	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() {
-			return nil
-		}
-		data, readErr := fs.ReadFile(fsys, p)
-		if readErr != nil {
-			return fmt.Errorf("reading %s: %w", p, readErr)
-		}
-		return RegisterFile(p, data)
-	})
-	if err != nil {
-		return nil, fmt.Errorf("walking FS: %w", err)
+	if err := registerFS(fsys, opts); err != nil {
+		return nil, err
 	}
-
-	norm := path.Clean(strings.ReplaceAll(mainFile, "\\", "/"))
-	norm = strings.TrimLeft(norm, "/")
-	url := "file:///" + norm
+	url := vfsURL(mainFile)
 
 	// Create async view: returns immediately, loading is processed in ticks
-	viewID := ulCreateViewAsync(int32(width), int32(height), url)
+	viewID := createView(opts, func() int32 {
+		return ulCreateViewAsync(int32(width), int32(height), url)
+	})
 	if viewID < 0 {
-		return nil, fmt.Errorf("ul_create_view_async failed with code %d", viewID)
+		return nil, createError("ul_create_view_async", viewID)
 	}
 	registerView()
 
-	ui := &UltralightUI{
-		viewID:  viewID,
-		texture: ebiten.NewImage(width, height),
-		pixels:  make([]byte, width*height*4),
-		width:   width,
-		height:  height,
-	}
+	ui := &UltralightUI{view: newView(viewID, width, height)}
+	ui.registerLive()
 	ui.detectMouseScale()
-
+	if err := ui.applyOpts(opts); err != nil {
+		return nil, err
+	}
 	return ui, nil
 }
@@ -0,0 +1,136 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// vfsContent holds a copy of each registered file while SetKeepVFSContent is
+// enabled, for VFSAsFS. Guarded by vfsMu.
+var (
+	keepVFSContent bool
+	vfsContent     = map[string][]byte{}
+)
+
+// SetKeepVFSContent makes the Go side keep a copy of every file registered
+// from now on, so VFSAsFS can read it back, e.g. in tests asserting what
+// NewFromFS registered. It doubles the memory of registered files, so leave it
+// off in production. Disabling it drops the copies kept so far.
+func SetKeepVFSContent(enabled bool) {
+	vfsMu.Lock()
+	defer vfsMu.Unlock()
+	keepVFSContent = enabled
+	if !enabled {
+		clear(vfsContent)
+	}
+}
+
+// VFSAsFS returns a read-only fs.FS over the VFS content kept since
+// SetKeepVFSContent(true), with the VFS paths as names:
+//
+//	data, err := fs.ReadFile(ultralightui.VFSAsFS(), "ui/index.html")
+//
+// It reflects later registrations and removals. Files registered while
+// content was not kept are missing from it (VFSFiles lists them all).
+func VFSAsFS() fs.FS {
+	return vfsFS{}
+}
+
+type vfsFS struct{}
+
+func (vfsFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	vfsMu.Lock()
+	defer vfsMu.Unlock()
+	if data, ok := vfsContent[name]; ok {
+		info := vfsInfo{name: path.Base(name), size: int64(len(data))}
+		return &vfsFile{info: info, Reader: bytes.NewReader(data)}, nil
+	}
+	// Directories are implied by the paths below them.
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	children := map[string]vfsInfo{}
+	for p, data := range vfsContent {
+		rest, ok := strings.CutPrefix(p, prefix)
+		if !ok {
+			continue
+		}
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			children[rest[:i]] = vfsInfo{name: rest[:i], dir: true}
+		} else {
+			children[rest] = vfsInfo{name: rest, size: int64(len(data))}
+		}
+	}
+	if len(children) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, c := range children {
+		entries = append(entries, fs.FileInfoToDirEntry(c))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return &vfsDir{info: vfsInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+// vfsInfo is the fs.FileInfo of a VFS file or implied directory.
+type vfsInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i vfsInfo) Name() string       { return i.name }
+func (i vfsInfo) Size() int64        { return i.size }
+func (i vfsInfo) ModTime() time.Time { return time.Time{} }
+func (i vfsInfo) IsDir() bool        { return i.dir }
+func (i vfsInfo) Sys() any           { return nil }
+func (i vfsInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+type vfsFile struct {
+	info vfsInfo
+	*bytes.Reader
+}
+
+func (f *vfsFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *vfsFile) Close() error               { return nil }
+
+type vfsDir struct {
+	info    vfsInfo
+	entries []fs.DirEntry
+	off     int
+}
+
+func (d *vfsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *vfsDir) Close() error               { return nil }
+func (d *vfsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *vfsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.off:]
+	if n > 0 && len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > 0 && n < len(rest) {
+		rest = rest[:n]
+	}
+	d.off += len(rest)
+	return rest, nil
+}
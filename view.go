@@ -0,0 +1,144 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"image"
+	"unsafe"
+)
+
+// view is the bridge view behind an UltralightUI and the RGBA frames copied
+// from it. It has no Ebiten dependency: the texture is created from it on
+// demand (GetTexture), and headless rendering uses it alone.
+type view struct {
+	viewID int32
+	width  int
+	height int
+
+	pixels   []byte // last complete frame, RGBA premultiplied, width*height*4
+	back     []byte // double buffering: next frame is copied here, then swapped
+	hasFrame bool   // pixels holds at least one copied frame
+
+	// dirty is the area of pixels updated by the last copy: the surface's
+	// dirty rect after a partial copy, the whole frame otherwise.
+	dirty image.Rectangle
+}
+
+func newView(viewID int32, width, height int) view {
+	return view{
+		viewID: viewID,
+		width:  width,
+		height: height,
+		pixels: make([]byte, width*height*4),
+	}
+}
+
+// setDoubleBuffer enables copying frames into a second buffer, so a slice
+// returned by Pixels isn't overwritten by the very next copy.
+func (v *view) setDoubleBuffer(enabled bool) {
+	if !enabled {
+		v.back = nil
+	} else if v.back == nil {
+		v.back = make([]byte, len(v.pixels))
+	}
+}
+
+// resize reallocates the frame buffers for a new view size. They hold no
+// frame until the next copy.
+func (v *view) resize(width, height int) {
+	v.width, v.height = width, height
+	v.pixels = make([]byte, width*height*4)
+	if v.back != nil {
+		v.back = make([]byte, len(v.pixels))
+	}
+	v.hasFrame = false
+	v.dirty = image.Rectangle{}
+}
+
+// copyFrame copies the surface into the frame buffer if Ultralight rendered
+// changes since the last copy. Returns true if a new frame was copied.
+// ul_view_copy_pixels_rgba checks the dirty bounds itself; with no changes it
+// returns 0 without copying (very cheap: just reads a rect).
+//
+// Once pixels holds a frame, only the dirty rect is converted into it
+// (ul_view_copy_pixels_rgba_dirty). Not with double buffering: the back
+// buffer holds an older frame, so it needs every pixel.
+func (v *view) copyFrame() bool {
+	if !v.hasFrame || v.back != nil || ulViewCopyPixelsDirty == nil {
+		return v.copyFrameWith(ulViewCopyPixelsRGBA)
+	}
+	if !v.copyFrameWith(ulViewCopyPixelsDirty) {
+		return false
+	}
+	if r := lastDirtyRect(v.viewID).Intersect(v.bounds()); !r.Empty() {
+		v.dirty = r
+	}
+	return true
+}
+
+// copyFrameForced copies the surface even if it has no dirty bounds. Bridges
+// without ul_view_copy_pixels_rgba_force fall back to copyFrame.
+func (v *view) copyFrameForced() bool {
+	if ulViewCopyPixelsForce == nil {
+		return v.copyFrame()
+	}
+	return v.copyFrameWith(ulViewCopyPixelsForce)
+}
+
+func (v *view) copyFrameWith(copyPixels func(viewID int32, dest uintptr, destSize int32) int32) bool {
+	dst := v.pixels
+	if v.back != nil {
+		dst = v.back
+	}
+	if len(dst) == 0 {
+		return false
+	}
+	if copyPixels(v.viewID, uintptr(unsafe.Pointer(&dst[0])), int32(len(dst))) == 0 {
+		return false
+	}
+	if v.back != nil {
+		v.pixels, v.back = v.back, v.pixels
+	}
+	v.hasFrame = true
+	v.dirty = v.bounds()
+	return true
+}
+
+func (v *view) bounds() image.Rectangle {
+	return image.Rect(0, 0, v.width, v.height)
+}
+
+// clipTo places the view at (x, y) relative to the destination bounds db and
+// returns the visible destination rect and the matching rect of the view.
+func (v *view) clipTo(db image.Rectangle, x, y int) (to, from image.Rectangle) {
+	at := image.Pt(x, y).Add(db.Min)
+	to = v.bounds().Add(at).Intersect(db)
+	return to, to.Sub(at)
+}
+
+// dirtyPixels packs the rows of the last copy's dirty rect from pixels into
+// buf (grown as needed), for uploading just that part of the texture.
+func (v *view) dirtyPixels(buf []byte) []byte {
+	return v.rectPixels(buf, v.dirty)
+}
+
+// rectPixels packs the rows of r, which must lie within the view, from
+// pixels into buf (grown as needed).
+func (v *view) rectPixels(buf []byte, r image.Rectangle) []byte {
+	n := r.Dx() * 4
+	buf = buf[:0]
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		off := (y*v.width + r.Min.X) * 4
+		buf = append(buf, v.pixels[off:off+n]...)
+	}
+	return buf
+}
+
+// releaseFrames drops the frame buffers.
+func (v *view) releaseFrames() {
+	v.pixels = nil
+	v.back = nil
+	v.hasFrame = false
+	v.dirty = image.Rectangle{}
+}
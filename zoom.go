@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Javier Podavini (YindSoft)
+// Licensed under the MIT License. See LICENSE file in the project root.
+
+package ultralightui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Zoom range accepted by SetZoom.
+const (
+	MinZoom = 0.5
+	MaxZoom = 3.0
+)
+
+// SetZoom sets the page zoom factor (1.0 = 100%), clamped to [MinZoom, MaxZoom].
+// Content reflows at the new logical size while the texture keeps its size;
+// the zoom is applied with CSS zoom on the root element, so mouse coordinates
+// keep mapping to the right elements. The zoom is (re)applied once the DOM is
+// ready, so it can be set right after creating the view.
+func (ui *UltralightUI) SetZoom(factor float64) {
+	factor = clampZoom(factor)
+	if factor == ui.GetZoom() && ui.zoomApplied {
+		return
+	}
+	ui.zoom = factor
+	ui.zoomApplied = false
+	if ui.domReady && !ui.closed.Load() {
+		ui.applyZoom()
+	}
+}
+
+// GetZoom returns the current zoom factor (1.0 if SetZoom was never called).
+func (ui *UltralightUI) GetZoom() float64 {
+	if ui.zoom == 0 {
+		return 1
+	}
+	return ui.zoom
+}
+
+func clampZoom(f float64) float64 {
+	if math.IsNaN(f) {
+		return 1
+	}
+	return math.Max(MinZoom, math.Min(MaxZoom, f))
+}
+
+// applyZoom sets CSS zoom on the document root.
+func (ui *UltralightUI) applyZoom() {
+	z := strconv.FormatFloat(ui.GetZoom(), 'f', -1, 64)
+	ui.Eval("if(document.documentElement)document.documentElement.style.zoom='" + z + "'")
+	ui.zoomApplied = true
+}
+
+// Device scale range accepted by Options.DeviceScale and SetDeviceScale.
+const (
+	minDeviceScale = 0.25
+	maxDeviceScale = 8.0
+)
+
+// SetDeviceScale changes the page's window.devicePixelRatio (see
+// Options.DeviceScale), e.g. when the window moves to a monitor with another
+// DPI. Resolution media queries such as (-webkit-min-device-pixel-ratio: 2)
+// are re-evaluated; the texture and view size don't change. Unlike SetZoom
+// this is a real device scale, not CSS zoom.
+func (ui *UltralightUI) SetDeviceScale(scale float64) error {
+	if ui.closed.Load() {
+		return ErrClosed
+	}
+	if ulViewSetDeviceScale == nil {
+		return errUnsupported("ul_view_set_device_scale")
+	}
+	switch rc := ulViewSetDeviceScale(ui.viewID, scalePermille(scale)); {
+	case rc == -3:
+		return errUnsupported("ulViewSetDeviceScale")
+	case rc < 0:
+		return fmt.Errorf("SetDeviceScale: bridge error %d", rc)
+	}
+	ui.deviceScale = normDeviceScale(scale)
+	return nil
+}
+
+// DeviceScale returns the page's devicePixelRatio (1 unless set with
+// Options.DeviceScale or SetDeviceScale).
+func (ui *UltralightUI) DeviceScale() float64 {
+	if ui.deviceScale == 0 {
+		return 1
+	}
+	return ui.deviceScale
+}
+
+// normDeviceScale clamps a device scale, mapping 0 and NaN to 1.
+func normDeviceScale(f float64) float64 {
+	if f == 0 || math.IsNaN(f) {
+		return 1
+	}
+	return math.Max(minDeviceScale, math.Min(maxDeviceScale, f))
+}
+
+// scalePermille converts a device scale to the bridge's permille.
+func scalePermille(f float64) int32 {
+	return int32(math.Round(normDeviceScale(f) * 1000))
+}